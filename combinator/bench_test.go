@@ -0,0 +1,151 @@
+package combinator
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// jsonValue builds a JSON parser from the library's public combinators.
+// Values are discarded into simple Go types; the parser exists to exercise the
+// hot paths (literals, identifiers, whitespace, alternatives) on realistic input.
+func jsonValue() Parser[any] {
+	var value Rule[any]
+
+	null := Map(Keyword("null"), func(string) any { return nil })
+	boolean := Map(Choice(Keyword("true"), Keyword("false")), func(s string) any { return s == "true" })
+	number := Map(Float(), func(f float64) any { return f })
+	str := Map(StringLit(), func(s string) any { return s })
+
+	array := Map(
+		Between(Symbol("["), Symbol("]"), SepBy(Lexeme(Ref(&value)), Symbol(","))),
+		func(items []any) any { return items },
+	)
+
+	member := Seq2(Left(StringToken(), Symbol(":")), Lexeme(Ref(&value)))
+	object := Map(
+		Between(Symbol("{"), Symbol("}"), SepBy(member, Symbol(","))),
+		func(members []Pair[string, any]) any {
+			m := make(map[string]any, len(members))
+			for _, kv := range members {
+				m[kv.First] = kv.Second
+			}
+			return m
+		},
+	)
+
+	value = func() Parser[any] {
+		return Choice(object, array, str, number, boolean, null)
+	}
+	return Right(Spaces(), Ref(&value))
+}
+
+// jsonDocument returns a JSON array of n records, roughly 120 bytes each.
+func jsonDocument(n int) string {
+	var sb strings.Builder
+	sb.WriteString("[\n")
+	for i := range n {
+		if i > 0 {
+			sb.WriteString(",\n")
+		}
+		fmt.Fprintf(&sb, `  {"id": %d, "name": "user_%d", "active": %t, "score": %d.5, "tags": ["a", "b"], "manager": null}`,
+			i, i, i%2 == 0, i*3)
+	}
+	sb.WriteString("\n]")
+	return sb.String()
+}
+
+func BenchmarkJSON(b *testing.B) {
+	parser := jsonValue()
+	input := jsonDocument(100)
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+
+	for b.Loop() {
+		if r := Parse(parser, input); !r.OK {
+			b.Fatal(r.Err)
+		}
+	}
+}
+
+func BenchmarkString(b *testing.B) {
+	parser := String("function")
+	b.ReportAllocs()
+
+	for b.Loop() {
+		Parse(parser, "function")
+	}
+}
+
+// BenchmarkStringState runs String on a prepared state, leaving out the rune
+// conversion of the input that every [Parse] call pays.
+func BenchmarkStringState(b *testing.B) {
+	parser := String("function")
+	state := NewState("function")
+	b.ReportAllocs()
+
+	for b.Loop() {
+		if r := parser(state); !r.OK {
+			b.Fatal(r.Err)
+		}
+	}
+}
+
+func BenchmarkIdent(b *testing.B) {
+	parser := Ident()
+	b.ReportAllocs()
+
+	for b.Loop() {
+		Parse(parser, "some_long_identifier_name42")
+	}
+}
+
+func BenchmarkSpaces(b *testing.B) {
+	parser := Spaces()
+	input := strings.Repeat(" \t\n", 20)
+	b.ReportAllocs()
+
+	for b.Loop() {
+		Parse(parser, input)
+	}
+}
+
+func BenchmarkChoiceFailure(b *testing.B) {
+	parser := Choice(String("while"), String("for"), String("if"), Ident())
+	b.ReportAllocs()
+
+	for b.Loop() {
+		Parse(parser, "identifier")
+	}
+}
+
+//nolint:paralleltest // tests share parser state
+func TestJSONBenchmarkGrammar(t *testing.T) {
+	t.Run("should parse benchmark document", func(t *testing.T) {
+		result := Parse(jsonValue(), jsonDocument(3))
+		if !result.OK {
+			t.Fatal(result.Err)
+		}
+		if items, ok := result.Value.([]any); !ok || len(items) != 3 {
+			t.Fatalf("unexpected value %#v", result.Value)
+		}
+	})
+}
+
+func BenchmarkParseChunks(b *testing.B) {
+	parser := jsonValue()
+	var sb strings.Builder
+	for range 200 {
+		sb.WriteString(strings.ReplaceAll(jsonDocument(1), "\n", " "))
+		sb.WriteByte('\n')
+	}
+	input := sb.String()
+	b.SetBytes(int64(len(input)))
+	b.ReportAllocs()
+
+	for b.Loop() {
+		if _, err := ParseChunks(parser, Newline(), input, 0); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -23,19 +23,16 @@ func Space() Parser[rune] {
 // Spaces matches zero or more whitespace characters.
 // Returns the matched whitespace as a string.
 // Always succeeds (returns empty string if no whitespace).
+// Optional whitespace is never reported by [Expected].
 func Spaces() Parser[string] {
-	return Map(Many(Space()), func(runes []rune) string {
-		return string(runes)
-	})
+	return TakeWhile(unicode.IsSpace)
 }
 
 // Spaces1 matches one or more whitespace characters.
 // Returns the matched whitespace as a string.
 // Fails if no whitespace is present.
 func Spaces1() Parser[string] {
-	return Map(Many1(Space()), func(runes []rune) string {
-		return string(runes)
-	})
+	return Label(TakeWhile1(unicode.IsSpace), "whitespace")
 }
 
 // Alpha matches a single ASCII letter (a-z, A-Z).
@@ -50,9 +47,11 @@ func Alpha() Parser[rune] {
 // AlphaNum matches a single Unicode letter or digit.
 // Returns the matched rune.
 func AlphaNum() Parser[rune] {
-	return Label(Satisfy(func(r rune) bool {
-		return unicode.IsLetter(r) || unicode.IsDigit(r)
-	}), "alphanumeric")
+	return Label(Satisfy(isAlphaNum), "alphanumeric")
+}
+
+func isAlphaNum(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
 }
 
 // Lower matches a single Unicode lowercase letter.
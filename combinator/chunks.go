@@ -0,0 +1,111 @@
+package combinator
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// chunk is a piece of input between two boundaries, from start up to end.
+type chunk struct {
+	start State
+	end   State
+}
+
+// ParseChunks splits input wherever boundary matches and parses the pieces
+// concurrently on workers goroutines, each with p applied to the whole piece.
+// Returns the values in input order. Suited to record-per-line formats such as
+// logs or NDJSON, where records can be parsed independently.
+//
+// Chunks keep their global positions, so values and errors report the line and
+// column in input, not in the chunk. Boundaries are not part of any chunk, and
+// empty chunks (e.g. after a trailing newline) are skipped. Zero-width boundary
+// matches are ignored. If workers is not positive, GOMAXPROCS workers are used.
+//
+// If any chunk fails, returns nil and the errors of all failed chunks joined
+// in input order. Options apply to each chunk's parse separately; handlers
+// given to [WithEvents] are called from several goroutines at once.
+//
+// Example:
+//
+//	values, err := ParseChunks(record, EndOfLine(), logs, 0)
+//	// err: expected ':' at line 3, col 9
+//	//      expected ':' at line 7, col 1
+func ParseChunks[T, B any](p Parser[T], boundary Parser[B], input string, workers int, opts ...Option) ([]T, error) {
+	chunks := splitChunks(boundary, NewState(input))
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, len(chunks))
+
+	whole := Left(p, EOF())
+	values := make([]T, len(chunks))
+	errs := make([]error, len(chunks))
+
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1)) - 1
+				if i >= len(chunks) {
+					return
+				}
+				r := run(whole, chunks[i].state(), opts)
+				values[i], errs[i] = r.Value, r.Err
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// splitChunks scans from state and returns the non-empty pieces between
+// matches of boundary.
+func splitChunks[B any](boundary Parser[B], state State) []chunk {
+	// Boundary failures at almost every position are expected; skip building their errors.
+	quiet := &session{quiet: true}
+
+	var chunks []chunk
+	add := func(c chunk) {
+		if c.end.Pos > c.start.Pos {
+			chunks = append(chunks, c)
+		}
+	}
+
+	start, current := state, state
+	for !current.IsEOF() {
+		probe := current
+		probe.session = quiet
+		r := boundary(probe)
+		if r.OK && r.State.Pos > current.Pos {
+			add(chunk{start: start, end: current})
+			next := r.State
+			next.session = nil
+			start, current = next, next
+			continue
+		}
+		current = current.Advance()
+	}
+	add(chunk{start: start, end: current})
+
+	return chunks
+}
+
+// state returns a State positioned at the chunk start whose input ends where
+// the chunk ends, so [EOF] matches at the boundary.
+func (c chunk) state() State {
+	s := c.start
+	s.Input = s.Input[:c.end.Pos]
+	if s.src != "" {
+		s.src = s.src[:c.end.off]
+	}
+	return s
+}
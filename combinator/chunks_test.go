@@ -0,0 +1,85 @@
+package combinator
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type logRecord struct {
+	level string
+	line  int
+}
+
+// logLine parses records like "info: started", remembering where they start.
+func logLine() Parser[logRecord] {
+	return func(state State) Result[logRecord] {
+		r := Left(Ident(), Seq2(Char(':'), TakeWhile(func(rune) bool { return true })))(state)
+		if !r.OK {
+			return Failure[logRecord](r.Err, r.State)
+		}
+		return Success(logRecord{level: r.Value, line: state.Line}, r.State)
+	}
+}
+
+//nolint:paralleltest // tests share parser state
+func TestParseChunks(t *testing.T) {
+	t.Run("should parse chunks in input order", func(t *testing.T) {
+		var sb strings.Builder
+		for i := range 500 {
+			fmt.Fprintf(&sb, "l%d: message %d\n", i, i)
+		}
+
+		values, err := ParseChunks(logLine(), EndOfLine(), sb.String(), 8)
+		require.NoError(t, err)
+		require.Len(t, values, 500)
+		for i, v := range values {
+			assert.Equal(t, fmt.Sprintf("l%d", i), v.level)
+			assert.Equal(t, i+1, v.line)
+		}
+	})
+
+	t.Run("should report errors at global positions in order", func(t *testing.T) {
+		input := "info: a\nwarn: b\ninfo c\r\nerror: d\n\n9: e\n"
+		values, err := ParseChunks(logLine(), EndOfLine(), input, 4)
+		require.Error(t, err)
+		assert.Nil(t, values)
+		assert.Equal(t, "expected ':', got ' ' at line 3, col 5\n"+
+			"expected identifier: unexpected '9' at line 6, col 1", err.Error())
+	})
+
+	t.Run("should split at multi-character boundaries", func(t *testing.T) {
+		sum := ChainL1(Integer(), Map(Char('+'), func(rune) func(a, b int64) int64 {
+			return func(a, b int64) int64 { return a + b }
+		}))
+		values, err := ParseChunks(sum, String("\n---\n"), "1+2\n---\n3+4+5", 0)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{3, 12}, values)
+	})
+
+	t.Run("should skip empty chunks", func(t *testing.T) {
+		values, err := ParseChunks(Integer(), Char(','), ",1,,2,", 2)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1, 2}, values)
+	})
+
+	t.Run("should return no values for empty input", func(t *testing.T) {
+		values, err := ParseChunks(Integer(), Char(','), "", 2)
+		require.NoError(t, err)
+		assert.Empty(t, values)
+	})
+
+	t.Run("should require each chunk to be consumed", func(t *testing.T) {
+		_, err := ParseChunks(Integer(), Char(';'), "1;2x;3", 2)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "col 4")
+	})
+
+	t.Run("should apply options to every chunk", func(t *testing.T) {
+		_, err := ParseChunks(nestedParens(), Char(';'), "(1);((2))", 2, WithMaxDepth(2))
+		require.ErrorIs(t, err, ErrDepthLimit)
+	})
+}
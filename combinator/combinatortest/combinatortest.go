@@ -0,0 +1,267 @@
+// Package combinatortest provides property-based testing helpers for grammars
+// built with the combinator package.
+//
+// The helpers plug into Go native fuzzing and check two families of properties:
+//
+//   - Round-trip: any value a parser accepts must survive printing and reparsing
+//     unchanged, i.e. parse(print(parse(s))) == parse(s).
+//   - Robustness: a parser must never panic, hang, or return a result that breaks
+//     the [combinator.Result] invariants, whatever the input.
+//
+// Failing inputs are shrunk with [Shrink] before being reported, so the failure
+// message points at a minimal reproduction rather than the raw fuzzer output.
+//
+// # Usage
+//
+//	func FuzzIntegerRoundTrip(f *testing.F) {
+//		combinatortest.FuzzRoundTrip(f, combinator.Integer(), func(v int64) string {
+//			return strconv.FormatInt(v, 10)
+//		}, combinatortest.WithSeeds("0", "-42", "9000"))
+//	}
+//
+//	func FuzzExprRobust(f *testing.F) {
+//		combinatortest.FuzzRobust(f, combinator.Ref(&expr), combinatortest.WithSeeds("(1+2)*3"))
+//	}
+package combinatortest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dottermi/x/combinator"
+)
+
+// Errors reported by the property checks.
+// Use [errors.Is] to distinguish failure kinds.
+var (
+	ErrPanic     = errors.New("parser panicked")
+	ErrTimeout   = errors.New("parser did not finish in time")
+	ErrInvariant = errors.New("parser broke a result invariant")
+	ErrRoundTrip = errors.New("round-trip mismatch")
+)
+
+const (
+	defaultTimeout   = time.Second
+	defaultMaxShrink = 500
+)
+
+type config struct {
+	seeds     []string
+	timeout   time.Duration
+	maxShrink int
+	equal     func(a, b any) bool
+}
+
+// Option configures the property checks.
+type Option func(*config)
+
+// WithSeeds adds inputs to the fuzz seed corpus.
+// Seeds are also executed by a plain "go test" run, without -fuzz.
+func WithSeeds(seeds ...string) Option {
+	return func(c *config) {
+		c.seeds = append(c.seeds, seeds...)
+	}
+}
+
+// WithTimeout sets how long a single parse may run before it is reported as a hang.
+// Defaults to one second. A hanging parse is then canceled through
+// [combinator.WithContext], which stops it at its next rule or repetition step.
+// A hand-written parser that loops without calling any combinator cannot be
+// stopped and keeps running in the background.
+func WithTimeout(d time.Duration) Option {
+	return func(c *config) {
+		c.timeout = d
+	}
+}
+
+// WithMaxShrink limits the number of candidate inputs tried while shrinking a failure.
+// Zero disables shrinking.
+func WithMaxShrink(n int) Option {
+	return func(c *config) {
+		c.maxShrink = n
+	}
+}
+
+// WithEqual replaces the value comparison used by round-trip checks.
+// Defaults to [reflect.DeepEqual].
+func WithEqual(equal func(a, b any) bool) Option {
+	return func(c *config) {
+		c.equal = equal
+	}
+}
+
+func newConfig(opts []Option) config {
+	c := config{
+		timeout:   defaultTimeout,
+		maxShrink: defaultMaxShrink,
+		equal:     reflect.DeepEqual,
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// FuzzRoundTrip registers a fuzz target checking that every value accepted by p
+// is reproduced when printed with print and parsed again.
+// Inputs that p rejects are ignored; the printed form must be consumed completely.
+// Panics and hangs are reported like in [FuzzRobust].
+func FuzzRoundTrip[T any](f *testing.F, p combinator.Parser[T], print func(T) string, opts ...Option) {
+	f.Helper()
+	cfg := newConfig(opts)
+	for _, seed := range cfg.seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		check := func(s string) error { return checkRoundTrip(p, print, s, cfg) }
+		if err := check(input); err != nil {
+			t.Fatal(report(input, err, check, cfg))
+		}
+	})
+}
+
+// FuzzRobust registers a fuzz target checking that p never panics, never exceeds
+// the configured timeout, and always returns a well-formed [combinator.Result].
+func FuzzRobust[T any](f *testing.F, p combinator.Parser[T], opts ...Option) {
+	f.Helper()
+	cfg := newConfig(opts)
+	for _, seed := range cfg.seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		check := func(s string) error { return checkRobust(p, s, cfg) }
+		if err := check(input); err != nil {
+			t.Fatal(report(input, err, check, cfg))
+		}
+	})
+}
+
+// CheckRoundTrip runs the round-trip property of [FuzzRoundTrip] on a single input.
+// Returns nil when the property holds or the input is rejected by p.
+func CheckRoundTrip[T any](p combinator.Parser[T], print func(T) string, input string, opts ...Option) error {
+	return checkRoundTrip(p, print, input, newConfig(opts))
+}
+
+// CheckRobust runs the robustness property of [FuzzRobust] on a single input.
+func CheckRobust[T any](p combinator.Parser[T], input string, opts ...Option) error {
+	return checkRobust(p, input, newConfig(opts))
+}
+
+func checkRoundTrip[T any](p combinator.Parser[T], print func(T) string, input string, cfg config) error {
+	first, err := run(p, input, cfg.timeout)
+	if err != nil {
+		return err
+	}
+	if !first.OK {
+		return nil
+	}
+
+	printed, err := guard(func() string { return print(first.Value) })
+	if err != nil {
+		return fmt.Errorf("printing %#v: %w", first.Value, err)
+	}
+
+	second, err := run(p, printed, cfg.timeout)
+	if err != nil {
+		return fmt.Errorf("reparsing %q: %w", printed, err)
+	}
+	if !second.OK {
+		return fmt.Errorf("%w: printed form %q rejected: %w", ErrRoundTrip, printed, second.Err)
+	}
+	if !second.State.IsEOF() {
+		return fmt.Errorf("%w: printed form %q only consumed up to line %d, col %d",
+			ErrRoundTrip, printed, second.State.Line, second.State.Col)
+	}
+	if !cfg.equal(first.Value, second.Value) {
+		return fmt.Errorf("%w: parsed %#v, printed %q, reparsed %#v", ErrRoundTrip, first.Value, printed, second.Value)
+	}
+	return nil
+}
+
+func checkRobust[T any](p combinator.Parser[T], input string, cfg config) error {
+	_, err := run(p, input, cfg.timeout)
+	return err
+}
+
+// run parses input with p, converting panics, hangs and malformed results into errors.
+// The parse is canceled when it runs out of time, so a hanging grammar does not
+// keep spinning after the hang is reported.
+func run[T any](p combinator.Parser[T], input string, timeout time.Duration) (combinator.Result[T], error) {
+	type outcome struct {
+		res combinator.Result[T]
+		err error
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan outcome, 1)
+	go func() {
+		res, err := guard(func() combinator.Result[T] {
+			return combinator.Parse(p, input, combinator.WithContext(ctx))
+		})
+		done <- outcome{res: res, err: err}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case out := <-done:
+		if out.err != nil {
+			return out.res, out.err
+		}
+		return out.res, validate(out.res, input)
+	case <-timer.C:
+		return combinator.Result[T]{}, fmt.Errorf("%w after %s", ErrTimeout, timeout)
+	}
+}
+
+// guard calls fn and converts a panic into an [ErrPanic] error.
+func guard[T any](fn func() T) (value T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrPanic, r)
+		}
+	}()
+	return fn(), nil
+}
+
+// validate checks the invariants every parser result must satisfy.
+func validate[T any](res combinator.Result[T], input string) error {
+	n := len([]rune(input))
+	switch {
+	case res.State.Pos < 0 || res.State.Pos > n:
+		return fmt.Errorf("%w: position %d outside input of length %d", ErrInvariant, res.State.Pos, n)
+	case res.State.Line < 1 || res.State.Col < 1:
+		return fmt.Errorf("%w: invalid line %d, col %d", ErrInvariant, res.State.Line, res.State.Col)
+	case !res.OK && res.Err == nil:
+		return fmt.Errorf("%w: failure without an error", ErrInvariant)
+	case res.OK && res.Err != nil:
+		return fmt.Errorf("%w: success carrying error %q", ErrInvariant, res.Err)
+	}
+	return nil
+}
+
+// report shrinks a failing input and formats the failure message.
+// Hangs are not shrunk since every candidate could take the full timeout.
+func report(input string, err error, check func(string) error, cfg config) string {
+	if errors.Is(err, ErrTimeout) || cfg.maxShrink == 0 {
+		return fmt.Sprintf("input %q: %v", input, err)
+	}
+
+	minimal := shrink(input, func(s string) bool {
+		e := check(s)
+		return e != nil && !errors.Is(e, ErrTimeout)
+	}, cfg.maxShrink)
+	if minimal == input {
+		return fmt.Sprintf("input %q: %v", input, err)
+	}
+	return fmt.Sprintf("input %q (shrunk from %q): %v", minimal, input, check(minimal))
+}
@@ -0,0 +1,183 @@
+package combinatortest
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dottermi/x/combinator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func formatInt(v int64) string {
+	return strconv.FormatInt(v, 10)
+}
+
+//nolint:paralleltest // tests share parser state
+func TestCheckRoundTrip(t *testing.T) {
+	t.Run("should pass when printed value reparses equal", func(t *testing.T) {
+		assert.NoError(t, CheckRoundTrip(combinator.Integer(), formatInt, "-42"))
+	})
+
+	t.Run("should ignore rejected inputs", func(t *testing.T) {
+		assert.NoError(t, CheckRoundTrip(combinator.Integer(), formatInt, "abc"))
+	})
+
+	t.Run("should report mismatched values", func(t *testing.T) {
+		err := CheckRoundTrip(combinator.Integer(), func(v int64) string {
+			return formatInt(v + 1)
+		}, "1")
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrRoundTrip)
+	})
+
+	t.Run("should report printed form that is not fully consumed", func(t *testing.T) {
+		err := CheckRoundTrip(combinator.Integer(), func(v int64) string {
+			return formatInt(v) + " "
+		}, "7")
+		assert.ErrorIs(t, err, ErrRoundTrip)
+	})
+
+	t.Run("should report printer panics", func(t *testing.T) {
+		err := CheckRoundTrip(combinator.Integer(), func(int64) string {
+			panic("boom")
+		}, "7")
+		assert.ErrorIs(t, err, ErrPanic)
+	})
+
+	t.Run("should use custom equality", func(t *testing.T) {
+		lower := combinator.Map(combinator.Ident(), strings.ToLower)
+		err := CheckRoundTrip(lower, strings.ToUpper, "Abc", WithEqual(func(a, b any) bool {
+			return strings.EqualFold(a.(string), b.(string))
+		}))
+		assert.NoError(t, err)
+	})
+}
+
+//nolint:paralleltest // tests share parser state
+func TestCheckRobust(t *testing.T) {
+	t.Run("should pass for well-behaved parser", func(t *testing.T) {
+		assert.NoError(t, CheckRobust(combinator.Ident(), "hello"))
+	})
+
+	t.Run("should report panics", func(t *testing.T) {
+		parser := combinator.Map(combinator.Any(), func(r rune) rune {
+			if r == '!' {
+				panic("bang")
+			}
+			return r
+		})
+		assert.ErrorIs(t, CheckRobust(parser, "!"), ErrPanic)
+	})
+
+	t.Run("should report hangs", func(t *testing.T) {
+		step := combinator.Lazy(func() combinator.Parser[rune] { return combinator.Any() })
+		stopped := make(chan struct{})
+		loop := func(s combinator.State) combinator.Result[struct{}] {
+			defer close(stopped)
+			for {
+				if r := step(s); !r.OK {
+					return combinator.Failure[struct{}](r.Err, s)
+				}
+			}
+		}
+
+		assert.ErrorIs(t, CheckRobust(loop, "x", WithTimeout(20*time.Millisecond)), ErrTimeout)
+
+		select {
+		case <-stopped:
+		case <-time.After(time.Second):
+			t.Fatal("hanging parser kept running after the timeout")
+		}
+	})
+
+	t.Run("should stop hanging repetitions", func(t *testing.T) {
+		slow := combinator.Map(combinator.Any(), func(r rune) rune {
+			time.Sleep(time.Millisecond)
+			return r
+		})
+		many := combinator.Many(slow)
+		stopped := make(chan struct{})
+		grammar := func(s combinator.State) combinator.Result[[]rune] {
+			defer close(stopped)
+			return many(s)
+		}
+
+		input := strings.Repeat("x", 10000)
+		assert.ErrorIs(t, CheckRobust(grammar, input, WithTimeout(20*time.Millisecond)), ErrTimeout)
+
+		select {
+		case <-stopped:
+		case <-time.After(time.Second):
+			t.Fatal("repetition kept running after the timeout")
+		}
+	})
+
+	t.Run("should report failures without error", func(t *testing.T) {
+		broken := func(s combinator.State) combinator.Result[rune] {
+			return combinator.Failure[rune](nil, s)
+		}
+		assert.ErrorIs(t, CheckRobust(broken, "x"), ErrInvariant)
+	})
+
+	t.Run("should report positions past the input", func(t *testing.T) {
+		broken := func(s combinator.State) combinator.Result[rune] {
+			s.Pos += 10
+			return combinator.Success('x', s)
+		}
+		assert.ErrorIs(t, CheckRobust(broken, "x"), ErrInvariant)
+	})
+}
+
+//nolint:paralleltest // tests share parser state
+func TestShrink(t *testing.T) {
+	t.Run("should reduce to minimal failing input", func(t *testing.T) {
+		minimal := Shrink("abc!def!ghi", func(s string) bool {
+			return strings.Contains(s, "!")
+		})
+		assert.Equal(t, "!", minimal)
+	})
+
+	t.Run("should keep required runes in order", func(t *testing.T) {
+		minimal := Shrink("xx(yy)zz", func(s string) bool {
+			return strings.Contains(s, "(") && strings.Contains(s, ")")
+		})
+		assert.Equal(t, "()", minimal)
+	})
+
+	t.Run("should return input when it does not fail", func(t *testing.T) {
+		assert.Equal(t, "abc", Shrink("abc", func(string) bool { return false }))
+	})
+
+	t.Run("should shrink a failing parser input", func(t *testing.T) {
+		parser := combinator.Map(combinator.Many(combinator.Any()), func(rs []rune) int {
+			for _, r := range rs {
+				if r == '#' {
+					panic("hash")
+				}
+			}
+			return len(rs)
+		})
+		minimal := Shrink("some # input", func(s string) bool {
+			return CheckRobust(parser, s) != nil
+		})
+		assert.Equal(t, "#", minimal)
+	})
+}
+
+func FuzzIntegerRoundTrip(f *testing.F) {
+	FuzzRoundTrip(f, combinator.Integer(), formatInt, WithSeeds("0", "-42", "9000", "007", "-"))
+}
+
+func FuzzStringLitRoundTrip(f *testing.F) {
+	FuzzRoundTrip(f, combinator.StringLit(), func(s string) string {
+		return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+	}, WithSeeds(`""`, `"hello"`, `"a \"quoted\" word"`, `"\\"`))
+}
+
+func FuzzSepByRobust(f *testing.F) {
+	list := combinator.Brackets(combinator.SepBy(combinator.Lexeme(combinator.Integer()), combinator.Symbol(",")))
+	FuzzRobust(f, list, WithSeeds("[]", "[1, 2, 3]", "[1,", "[[", "]"))
+}
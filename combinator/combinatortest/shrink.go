@@ -0,0 +1,51 @@
+package combinatortest
+
+// Shrink reduces input to a smaller string for which failing still reports true.
+// Uses delta debugging over runes: removes progressively smaller chunks until no
+// single rune can be dropped without making the failure disappear.
+//
+// Returns input unchanged if failing(input) is false.
+//
+// Example:
+//
+//	minimal := Shrink("1+2+(3*", func(s string) bool {
+//		return CheckRobust(parser, s) != nil
+//	})
+func Shrink(input string, failing func(string) bool) string {
+	return shrink(input, failing, -1)
+}
+
+// shrink implements [Shrink] with an optional budget of candidate evaluations.
+// A negative budget means unlimited.
+func shrink(input string, failing func(string) bool, budget int) string {
+	if !failing(input) {
+		return input
+	}
+
+	current := []rune(input)
+	chunk := len(current) / 2
+
+	for chunk >= 1 && budget != 0 {
+		reduced := false
+
+		for start := 0; start+chunk <= len(current) && budget != 0; {
+			candidate := make([]rune, 0, len(current)-chunk)
+			candidate = append(candidate, current[:start]...)
+			candidate = append(candidate, current[start+chunk:]...)
+
+			budget--
+			if failing(string(candidate)) {
+				current = candidate
+				reduced = true
+				continue
+			}
+			start += chunk
+		}
+
+		if !reduced {
+			chunk /= 2
+		}
+	}
+
+	return string(current)
+}
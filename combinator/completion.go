@@ -0,0 +1,112 @@
+package combinator
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Expectation describes an item a parser would have accepted where the input ended.
+// Literal expectations come from [Char], [String] and the parsers built on them
+// ([Keyword], [Symbol]); labeled ones come from [Label].
+type Expectation struct {
+	Text  string // Text is the expected literal, or the name given to [Label].
+	Label bool   // Label is true when Text names a parser rather than literal text.
+	Pos   int    // Pos is the rune offset where the expected item starts.
+	Line  int    // Line is the line where the expected item starts (1-indexed).
+	Col   int    // Col is the column where the expected item starts (1-indexed).
+}
+
+// Expected runs a parser over input truncated at the cursor and reports what could
+// legally appear next. Each literal or label is reported once per start position.
+//
+// An expectation may start before the end of input when the text typed so far is a
+// prefix of it: for "sel" and Keyword("select"), Pos is the offset of "sel", so the
+// caller knows which range a completion replaces.
+//
+// Example:
+//
+//	cmd := Seq2(Symbol("git"), Choice(Keyword("commit"), Keyword("checkout")))
+//	for _, e := range Expected(cmd, "git c") {
+//		fmt.Println(e.Text, e.Pos) // "commit" 4, "checkout" 4
+//	}
+func Expected[T any](p Parser[T], input string) []Expectation {
+	state := NewState(input)
+	state.session = &session{tracking: true}
+	p(state)
+
+	var result []Expectation
+	for _, e := range state.session.expected {
+		if !slices.Contains(result, e) {
+			result = append(result, e)
+		}
+	}
+
+	slices.SortStableFunc(result, func(a, b Expectation) int {
+		return cmp.Compare(a.Pos, b.Pos)
+	})
+	return result
+}
+
+// Completions returns the literal strings that could legally follow input.
+// Labeled expectations are skipped since they describe a class of text rather
+// than text that can be inserted.
+//
+// Example:
+//
+//	Completions(Choice(Keyword("select"), Keyword("set")), "se") // ["select", "set"]
+func Completions[T any](p Parser[T], input string) []string {
+	var result []string
+	for _, e := range Expected(p, input) {
+		if !e.Label && !slices.Contains(result, e.Text) {
+			result = append(result, e.Text)
+		}
+	}
+	return result
+}
+
+// expect records that text could have been accepted at s.
+// Does nothing unless the parse was started by [Expected].
+func (s GState[I]) expect(text string, label bool) {
+	if s.session == nil || !s.session.tracking {
+		return
+	}
+	s.session.expected = append(s.session.expected, Expectation{
+		Text:  text,
+		Label: label,
+		Pos:   s.Pos,
+		Line:  s.Line,
+		Col:   s.Col,
+	})
+}
+
+// relabel replaces expectations recorded at s since mark with a single label.
+// Expectations that start further along the input are kept, so a label only
+// describes failures that happened before the labeled parser consumed anything.
+func (s GState[I]) relabel(mark int, label string) {
+	if s.session == nil || !s.session.tracking {
+		return
+	}
+
+	kept := s.session.expected[:mark]
+	replaced := s.IsEOF()
+	for _, e := range s.session.expected[mark:] {
+		if e.Pos == s.Pos {
+			replaced = true
+			continue
+		}
+		kept = append(kept, e)
+	}
+	s.session.expected = kept
+
+	if replaced {
+		s.expect(label, true)
+	}
+}
+
+// mark returns the current number of recorded expectations.
+func (s GState[I]) mark() int {
+	if s.session == nil {
+		return 0
+	}
+	return len(s.session.expected)
+}
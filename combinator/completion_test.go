@@ -0,0 +1,91 @@
+package combinator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//nolint:paralleltest // tests share parser state
+func TestExpected(t *testing.T) {
+	t.Run("should report literals after consumed input", func(t *testing.T) {
+		cmd := Seq2(Symbol("git"), Choice(Keyword("commit"), Keyword("checkout")))
+		expected := Expected(cmd, "git ")
+
+		require.Len(t, expected, 2)
+		assert.Equal(t, Expectation{Text: "commit", Pos: 4, Line: 1, Col: 5}, expected[0])
+		assert.Equal(t, "checkout", expected[1].Text)
+	})
+
+	t.Run("should report literals partially typed at the cursor", func(t *testing.T) {
+		cmd := Seq2(Symbol("git"), Choice(Keyword("commit"), Keyword("checkout"), Keyword("push")))
+		expected := Expected(cmd, "git ch")
+
+		require.Len(t, expected, 1)
+		assert.Equal(t, "checkout", expected[0].Text)
+		assert.Equal(t, 4, expected[0].Pos)
+	})
+
+	t.Run("should report labels instead of inner expectations", func(t *testing.T) {
+		expected := Expected(Seq2(Char('x'), Digit()), "x")
+
+		require.Len(t, expected, 1)
+		assert.Equal(t, Expectation{Text: "digit", Label: true, Pos: 1, Line: 1, Col: 2}, expected[0])
+	})
+
+	t.Run("should replace nested labels with the outermost one", func(t *testing.T) {
+		expected := Expected(EndOfLine(), "")
+
+		require.Len(t, expected, 1)
+		assert.Equal(t, "end of line", expected[0].Text)
+	})
+
+	t.Run("should keep expectations past the label start", func(t *testing.T) {
+		pair := Label(Seq2(Char('('), Char(')')), "pair")
+		expected := Expected(pair, "(")
+
+		require.Len(t, expected, 1)
+		assert.Equal(t, ")", expected[0].Text)
+		assert.False(t, expected[0].Label)
+	})
+
+	t.Run("should include continuations of repeated parsers", func(t *testing.T) {
+		stmts := Seq2(Many(Symbol("a")), Symbol("end"))
+		assert.Equal(t, []string{"a", "end"}, Completions(stmts, "a a "))
+	})
+
+	t.Run("should track line and column", func(t *testing.T) {
+		expected := Expected(Seq2(Symbol("let"), Symbol("=")), "let\n  ")
+
+		require.Len(t, expected, 1)
+		assert.Equal(t, 2, expected[0].Line)
+		assert.Equal(t, 3, expected[0].Col)
+	})
+
+	t.Run("should ignore mismatches before the end of input", func(t *testing.T) {
+		assert.Empty(t, Expected(Choice(String("abc"), String("xyz")), "q"))
+	})
+
+	t.Run("should not record expectations in plain parses", func(t *testing.T) {
+		state := NewState("")
+		Char('a')(state)
+		assert.Nil(t, state.session)
+	})
+}
+
+//nolint:paralleltest // tests share parser state
+func TestCompletions(t *testing.T) {
+	t.Run("should return literal candidates", func(t *testing.T) {
+		kw := Choice(Keyword("select"), Keyword("set"), Keyword("update"))
+		assert.Equal(t, []string{"select", "set"}, Completions(kw, "se"))
+	})
+
+	t.Run("should skip labels", func(t *testing.T) {
+		assert.Empty(t, Completions(Digit(), ""))
+	})
+
+	t.Run("should return nothing after complete input", func(t *testing.T) {
+		assert.Empty(t, Completions(Seq2(String("ab"), EOF()), "ab"))
+	})
+}
@@ -0,0 +1,316 @@
+// Package cst builds lossless concrete syntax trees with the combinator package.
+//
+// Formatters and refactoring tools need every space and comment of the input,
+// which ordinary parsers discard. Parsers built with a [Lexer] attach that
+// trivia to the tokens around it, and [Node] groups tokens into a tree, so the
+// tree's [GreenNode.FullText] reproduces the input byte for byte.
+//
+// # Green and Red Trees
+//
+// The parser produces immutable [GreenNode] values that know their kind, text
+// and width but not their position, so identical subtrees can be shared and
+// edited trees rebuilt cheaply. [NewRoot] wraps a green tree in [SyntaxNode]
+// values that add parent links and byte offsets on demand for navigation.
+//
+// # Trivia
+//
+// A token owns the trivia following it up to and including the next newline
+// (trailing trivia); anything after that belongs to the next token (leading
+// trivia). Trivia after the last token is kept by the token returned from
+// [Lexer.End]. In the input
+//
+//	x = 1 // one
+//
+//	y = 2
+//
+// the token "1" has the trailing trivia " ", "// one" and "\n", and the token
+// "y" has the leading trivia "\n".
+package cst
+
+import (
+	"iter"
+	"strings"
+)
+
+// TriviaKind classifies a piece of trivia.
+type TriviaKind uint8
+
+const (
+	// Whitespace is a run of spaces, tabs and other non-newline whitespace.
+	Whitespace TriviaKind = iota
+	// Newline is a single line feed.
+	Newline
+	// Comment is a comment matched by one of the [Lexer] comment parsers.
+	Comment
+)
+
+// Trivia is input text between tokens that carries no syntactic meaning.
+type Trivia struct {
+	Kind TriviaKind
+	Text string
+}
+
+// GreenNode is an immutable syntax tree element without position information.
+// A token has text and trivia but no children; a node has children only.
+type GreenNode struct {
+	kind     string
+	text     string
+	leading  []Trivia
+	trailing []Trivia
+	children []*GreenNode
+	token    bool
+	width    int // width is the length in bytes of the full text.
+}
+
+// NewToken returns a green token with its surrounding trivia.
+func NewToken(kind, text string, leading, trailing []Trivia) *GreenNode {
+	width := len(text)
+	for _, t := range leading {
+		width += len(t.Text)
+	}
+	for _, t := range trailing {
+		width += len(t.Text)
+	}
+	return &GreenNode{kind: kind, text: text, leading: leading, trailing: trailing, token: true, width: width}
+}
+
+// NewNode returns a green node with the given children.
+func NewNode(kind string, children []*GreenNode) *GreenNode {
+	width := 0
+	for _, c := range children {
+		width += c.width
+	}
+	return &GreenNode{kind: kind, children: children, width: width}
+}
+
+// Kind returns the token or node kind.
+func (g *GreenNode) Kind() string {
+	return g.kind
+}
+
+// IsToken reports whether g is a token rather than a node.
+func (g *GreenNode) IsToken() bool {
+	return g.token
+}
+
+// Text returns the text of a token without its trivia, or the text of a node
+// from its first token to its last token.
+func (g *GreenNode) Text() string {
+	if g.token {
+		return g.text
+	}
+	full := g.FullText()
+	return full[g.leadingWidth() : len(full)-g.trailingWidth()]
+}
+
+// FullText returns the text including all trivia, exactly as it appeared in the input.
+func (g *GreenNode) FullText() string {
+	var sb strings.Builder
+	sb.Grow(g.width)
+	g.write(&sb)
+	return sb.String()
+}
+
+// Leading returns the trivia before a token. Nodes have no trivia of their own.
+func (g *GreenNode) Leading() []Trivia {
+	return g.leading
+}
+
+// Trailing returns the trivia after a token. Nodes have no trivia of their own.
+func (g *GreenNode) Trailing() []Trivia {
+	return g.trailing
+}
+
+// Children returns the children of a node, or nil for a token.
+func (g *GreenNode) Children() []*GreenNode {
+	return g.children
+}
+
+// Width returns the length in bytes of the full text.
+func (g *GreenNode) Width() int {
+	return g.width
+}
+
+func (g *GreenNode) write(sb *strings.Builder) {
+	if !g.token {
+		for _, c := range g.children {
+			c.write(sb)
+		}
+		return
+	}
+	for _, t := range g.leading {
+		sb.WriteString(t.Text)
+	}
+	sb.WriteString(g.text)
+	for _, t := range g.trailing {
+		sb.WriteString(t.Text)
+	}
+}
+
+// leadingWidth returns the byte length of the trivia before the first token.
+func (g *GreenNode) leadingWidth() int {
+	for g != nil && !g.token {
+		g = firstNonEmpty(g.children)
+	}
+	if g == nil {
+		return 0
+	}
+	width := 0
+	for _, t := range g.leading {
+		width += len(t.Text)
+	}
+	return width
+}
+
+// trailingWidth returns the byte length of the trivia after the last token.
+func (g *GreenNode) trailingWidth() int {
+	for g != nil && !g.token {
+		g = lastNonEmpty(g.children)
+	}
+	if g == nil {
+		return 0
+	}
+	width := 0
+	for _, t := range g.trailing {
+		width += len(t.Text)
+	}
+	return width
+}
+
+func firstNonEmpty(children []*GreenNode) *GreenNode {
+	for _, c := range children {
+		if c.token || c.width > 0 {
+			return c
+		}
+	}
+	return nil
+}
+
+func lastNonEmpty(children []*GreenNode) *GreenNode {
+	for i := len(children) - 1; i >= 0; i-- {
+		if c := children[i]; c.token || c.width > 0 {
+			return c
+		}
+	}
+	return nil
+}
+
+// SyntaxNode is a green element positioned in a tree, with its parent and byte offset.
+// SyntaxNodes are created lazily while navigating from the root.
+type SyntaxNode struct {
+	green  *GreenNode
+	parent *SyntaxNode
+	offset int
+}
+
+// NewRoot returns the root of the tree for g, starting at offset 0.
+func NewRoot(g *GreenNode) *SyntaxNode {
+	return &SyntaxNode{green: g}
+}
+
+// Green returns the underlying green element.
+func (n *SyntaxNode) Green() *GreenNode {
+	return n.green
+}
+
+// Kind returns the token or node kind.
+func (n *SyntaxNode) Kind() string {
+	return n.green.kind
+}
+
+// IsToken reports whether n is a token rather than a node.
+func (n *SyntaxNode) IsToken() bool {
+	return n.green.token
+}
+
+// Parent returns the enclosing node, or nil for the root.
+func (n *SyntaxNode) Parent() *SyntaxNode {
+	return n.parent
+}
+
+// Offset returns the byte offset of the full text, including leading trivia.
+func (n *SyntaxNode) Offset() int {
+	return n.offset
+}
+
+// Span returns the half-open byte range [start, end) of the text without the
+// leading trivia of its first token and the trailing trivia of its last token.
+func (n *SyntaxNode) Span() (start, end int) {
+	return n.offset + n.green.leadingWidth(), n.offset + n.green.width - n.green.trailingWidth()
+}
+
+// Text returns the text without surrounding trivia. See [GreenNode.Text].
+func (n *SyntaxNode) Text() string {
+	return n.green.Text()
+}
+
+// FullText returns the text including all trivia. See [GreenNode.FullText].
+func (n *SyntaxNode) FullText() string {
+	return n.green.FullText()
+}
+
+// Children returns the positioned children of a node, or nil for a token.
+func (n *SyntaxNode) Children() []*SyntaxNode {
+	if len(n.green.children) == 0 {
+		return nil
+	}
+	children := make([]*SyntaxNode, len(n.green.children))
+	offset := n.offset
+	for i, g := range n.green.children {
+		children[i] = &SyntaxNode{green: g, parent: n, offset: offset}
+		offset += g.width
+	}
+	return children
+}
+
+// Descendants returns n and every element below it in depth-first pre-order.
+func (n *SyntaxNode) Descendants() iter.Seq[*SyntaxNode] {
+	return func(yield func(*SyntaxNode) bool) {
+		n.walk(yield)
+	}
+}
+
+func (n *SyntaxNode) walk(yield func(*SyntaxNode) bool) bool {
+	if !yield(n) {
+		return false
+	}
+	for _, c := range n.Children() {
+		if !c.walk(yield) {
+			return false
+		}
+	}
+	return true
+}
+
+// Tokens returns the tokens below n in input order.
+func (n *SyntaxNode) Tokens() iter.Seq[*SyntaxNode] {
+	return func(yield func(*SyntaxNode) bool) {
+		for d := range n.Descendants() {
+			if d.IsToken() && !yield(d) {
+				return
+			}
+		}
+	}
+}
+
+// TokenAt returns the token whose full text, including trivia, covers the byte
+// offset, or nil if the offset is outside n.
+func (n *SyntaxNode) TokenAt(offset int) *SyntaxNode {
+	if offset < n.offset || offset >= n.offset+n.green.width {
+		return nil
+	}
+	for !n.IsToken() {
+		next := (*SyntaxNode)(nil)
+		for _, c := range n.Children() {
+			if offset >= c.offset && offset < c.offset+c.green.width {
+				next = c
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		n = next
+	}
+	return n
+}
@@ -0,0 +1,188 @@
+package cst
+
+import (
+	"slices"
+	"testing"
+	"unicode"
+
+	"github.com/dottermi/x/combinator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// assignments parses lines like "x = [1, 2] // note" into a lossless tree.
+func assignments() combinator.Parser[*GreenNode] {
+	lx := NewLexer(LineComment("//"), BlockComment("/*", "*/"))
+	name := lx.Lexeme("Name", combinator.Ident())
+	number := lx.Lexeme("Number", combinator.TakeWhile1(unicode.IsDigit))
+
+	var value combinator.Parser[*GreenNode]
+	list := Node("List",
+		One(lx.Symbol("[")),
+		SepBy(One(combinator.Lazy(func() combinator.Parser[*GreenNode] { return value })), One(lx.Symbol(","))),
+		One(lx.Symbol("]")),
+	)
+	value = combinator.Choice(number, list)
+
+	assign := Node("Assign", One(name), One(lx.Symbol("=")), One(value))
+	return Node("File", Many(One(assign)), One(lx.End()))
+}
+
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	inputs := []string{
+		"",
+		"x = 1",
+		"x=1\ny=2\n",
+		"  // header\n\nx = [1, /* two */ 2,3] // trailing\n\n\ty = [ ]\n  // footer\n",
+		"a = [[1], [2, [3]]]\r\n",
+	}
+
+	for _, input := range inputs {
+		root, err := Parse(assignments(), input)
+		require.NoError(t, err, "input %q", input)
+		assert.Equal(t, input, root.FullText())
+		assert.Equal(t, len(input), root.Green().Width())
+	}
+}
+
+func TestTrivia(t *testing.T) {
+	t.Parallel()
+
+	root, err := Parse(assignments(), "x = 1 // one\n\ny = 2")
+	require.NoError(t, err)
+
+	tokens := slices.Collect(root.Tokens())
+	require.Len(t, tokens, 7)
+
+	t.Run("should attach trivia up to the newline as trailing", func(t *testing.T) {
+		t.Parallel()
+		one := tokens[2].Green()
+		assert.Equal(t, "1", one.Text())
+		assert.Equal(t, []Trivia{
+			{Kind: Whitespace, Text: " "},
+			{Kind: Comment, Text: "// one"},
+			{Kind: Newline, Text: "\n"},
+		}, one.Trailing())
+	})
+
+	t.Run("should attach remaining trivia as leading", func(t *testing.T) {
+		t.Parallel()
+		y := tokens[3].Green()
+		assert.Equal(t, "y", y.Text())
+		assert.Equal(t, []Trivia{{Kind: Newline, Text: "\n"}}, y.Leading())
+	})
+
+	t.Run("should end with an empty end token", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, EndKind, tokens[6].Kind())
+		assert.Empty(t, tokens[6].Text())
+	})
+}
+
+func TestSyntaxNode(t *testing.T) {
+	t.Parallel()
+
+	input := "x = 1\n  y = [2, 3] // list\n"
+	root, err := Parse(assignments(), input)
+	require.NoError(t, err)
+
+	var assigns []*SyntaxNode
+	for n := range root.Descendants() {
+		if n.Kind() == "Assign" {
+			assigns = append(assigns, n)
+		}
+	}
+	require.Len(t, assigns, 2)
+
+	t.Run("should trim surrounding trivia from text", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "y = [2, 3]", assigns[1].Text())
+		assert.Equal(t, "  y = [2, 3] // list\n", assigns[1].FullText())
+	})
+
+	t.Run("should report byte offsets", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, 6, assigns[1].Offset())
+		start, end := assigns[1].Span()
+		assert.Equal(t, "y = [2, 3]", input[start:end])
+	})
+
+	t.Run("should link children to parents", func(t *testing.T) {
+		t.Parallel()
+		list := assigns[1].Children()[2]
+		assert.Equal(t, "List", list.Kind())
+		assert.Same(t, assigns[1], list.Parent())
+		assert.Nil(t, root.Parent())
+	})
+
+	t.Run("should find the token at an offset", func(t *testing.T) {
+		t.Parallel()
+		tok := root.TokenAt(13)
+		require.NotNil(t, tok)
+		assert.Equal(t, "2", tok.Text())
+		assert.Equal(t, "Number", tok.Kind())
+
+		owner := root.TokenAt(20)
+		require.NotNil(t, owner)
+		assert.Equal(t, "]", owner.Text())
+
+		assert.Nil(t, root.TokenAt(len(input)))
+	})
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should fail on invalid input", func(t *testing.T) {
+		t.Parallel()
+		_, err := Parse(assignments(), "x = [1,")
+		require.Error(t, err)
+	})
+
+	t.Run("should fail on unconsumed input", func(t *testing.T) {
+		t.Parallel()
+		lx := NewLexer()
+		_, err := Parse(lx.Symbol("a"), "a b")
+		require.Error(t, err)
+	})
+}
+
+func TestLexeme(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should keep the original spelling of decoded values", func(t *testing.T) {
+		t.Parallel()
+		lx := NewLexer()
+		r := combinator.Parse(lx.Lexeme("String", combinator.StringLit()), `"a\nb" `)
+		require.True(t, r.OK)
+		assert.Equal(t, `"a\nb"`, r.Value.Text())
+		assert.Equal(t, `"a\nb" `, r.Value.FullText())
+	})
+
+	t.Run("should fail at the start of leading trivia", func(t *testing.T) {
+		t.Parallel()
+		lx := NewLexer()
+		r := combinator.Parse(lx.Symbol("a"), "   b")
+		require.False(t, r.OK)
+		assert.Equal(t, 0, r.State.Pos)
+	})
+}
+
+func TestGreenNode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should share identical subtrees", func(t *testing.T) {
+		t.Parallel()
+		one := NewToken("Number", "1", nil, []Trivia{{Kind: Whitespace, Text: " "}})
+		pair := NewNode("Pair", []*GreenNode{one, one})
+		assert.Equal(t, "1 1 ", pair.FullText())
+		assert.Equal(t, "1 1", pair.Text())
+		assert.Equal(t, 4, pair.Width())
+
+		children := NewRoot(pair).Children()
+		assert.Equal(t, 0, children[0].Offset())
+		assert.Equal(t, 2, children[1].Offset())
+	})
+}
@@ -0,0 +1,190 @@
+package cst
+
+import (
+	"unicode"
+
+	"github.com/dottermi/x/combinator"
+)
+
+// EndKind is the kind of the token returned by [Lexer.End].
+const EndKind = "EOF"
+
+// Lexer builds token parsers that collect the trivia around each token.
+// Whitespace is always trivia; comments are trivia when matched by one of the
+// comment parsers given to [NewLexer].
+type Lexer struct {
+	leading  combinator.Parser[[]Trivia]
+	trailing combinator.Parser[[]Trivia]
+}
+
+// NewLexer returns a lexer treating whitespace and the given comments as trivia.
+//
+// Example:
+//
+//	lx := cst.NewLexer(cst.LineComment("//"), cst.BlockComment("/*", "*/"))
+func NewLexer(comments ...combinator.Parser[string]) *Lexer {
+	space := combinator.Map(combinator.TakeWhile1(func(r rune) bool {
+		return r != '\n' && unicode.IsSpace(r)
+	}), func(s string) Trivia { return Trivia{Kind: Whitespace, Text: s} })
+
+	newline := combinator.Map(combinator.Char('\n'), func(rune) Trivia {
+		return Trivia{Kind: Newline, Text: "\n"}
+	})
+
+	inline := []combinator.Parser[Trivia]{space}
+	for _, c := range comments {
+		inline = append(inline, combinator.Map(c, func(s string) Trivia {
+			return Trivia{Kind: Comment, Text: s}
+		}))
+	}
+	sameLine := combinator.Many(combinator.Choice(inline...))
+
+	return &Lexer{
+		leading: combinator.Many(combinator.Choice(append(inline, newline)...)),
+		trailing: combinator.Map(combinator.Seq2(sameLine, combinator.Opt(newline)), func(p combinator.Pair[[]Trivia, *Trivia]) []Trivia {
+			if p.Second != nil {
+				return append(p.First, *p.Second)
+			}
+			return p.First
+		}),
+	}
+}
+
+// Spaces matches any amount of trivia and returns it.
+// Token parsers already collect trivia; use Spaces for custom token parsers.
+func (l *Lexer) Spaces() combinator.Parser[[]Trivia] {
+	return l.leading
+}
+
+// Lexeme matches p as a token of the given kind, together with the trivia
+// before it and the trailing trivia after it. The token text is the input
+// consumed by p, so parsers returning decoded values such as
+// [combinator.StringLit] keep their original spelling.
+//
+// Example:
+//
+//	number := lx.Lexeme("Number", combinator.Recognize(combinator.Integer()))
+func (l *Lexer) Lexeme(kind string, p combinator.Parser[string]) combinator.Parser[*GreenNode] {
+	text := combinator.Recognize(p)
+
+	return func(state combinator.State) combinator.Result[*GreenNode] {
+		lead := l.leading(state)
+		tok := text(lead.State)
+		if !tok.OK {
+			return combinator.Failure[*GreenNode](tok.Err, state)
+		}
+		trail := l.trailing(tok.State)
+		return combinator.Success(NewToken(kind, tok.Value, lead.Value, trail.Value), trail.State)
+	}
+}
+
+// Symbol matches the literal s as a token whose kind is s itself.
+//
+// Example:
+//
+//	comma := lx.Symbol(",")
+func (l *Lexer) Symbol(s string) combinator.Parser[*GreenNode] {
+	return l.Lexeme(s, combinator.String(s))
+}
+
+// End matches the end of input, returning an empty token of kind [EndKind]
+// that keeps the trivia after the last token. Root parsers should end with it
+// so that the tree covers the whole input.
+func (l *Lexer) End() combinator.Parser[*GreenNode] {
+	return l.Lexeme(EndKind, combinator.Map(combinator.EOF(), func(struct{}) string { return "" }))
+}
+
+// LineComment matches a comment from prefix up to, but not including, the end of the line.
+func LineComment(prefix string) combinator.Parser[string] {
+	return combinator.Recognize(combinator.Seq2(
+		combinator.String(prefix),
+		combinator.TakeWhile(func(r rune) bool { return r != '\n' }),
+	))
+}
+
+// BlockComment matches a comment enclosed by open and closing, which may span lines.
+func BlockComment(open, closing string) combinator.Parser[string] {
+	return combinator.Recognize(combinator.Seq3(
+		combinator.String(open),
+		combinator.TakeUntil(combinator.String(closing)),
+		combinator.String(closing),
+	))
+}
+
+// Node runs the parts in sequence and groups their elements into a node of the given kind.
+//
+// Example:
+//
+//	list := cst.Node("List",
+//		cst.One(lx.Symbol("[")),
+//		cst.SepBy(cst.One(number), cst.One(lx.Symbol(","))),
+//		cst.One(lx.Symbol("]")),
+//	)
+func Node(kind string, parts ...combinator.Parser[[]*GreenNode]) combinator.Parser[*GreenNode] {
+	return combinator.Map(Seq(parts...), func(children []*GreenNode) *GreenNode {
+		return NewNode(kind, children)
+	})
+}
+
+// One turns a token or node parser into a part for [Node].
+func One(p combinator.Parser[*GreenNode]) combinator.Parser[[]*GreenNode] {
+	return combinator.Map(p, func(g *GreenNode) []*GreenNode { return []*GreenNode{g} })
+}
+
+// Seq runs the parts in sequence and concatenates their elements.
+func Seq(parts ...combinator.Parser[[]*GreenNode]) combinator.Parser[[]*GreenNode] {
+	return func(state combinator.State) combinator.Result[[]*GreenNode] {
+		var children []*GreenNode
+		current := state
+
+		for _, part := range parts {
+			r := part(current)
+			if !r.OK {
+				return combinator.Failure[[]*GreenNode](r.Err, r.State)
+			}
+			children = append(children, r.Value...)
+			current = r.State
+		}
+
+		return combinator.Success(children, current)
+	}
+}
+
+// Many matches a part zero or more times and concatenates the elements.
+func Many(part combinator.Parser[[]*GreenNode]) combinator.Parser[[]*GreenNode] {
+	return combinator.Map(combinator.Many(part), concat)
+}
+
+// Opt matches a part zero or one time.
+func Opt(part combinator.Parser[[]*GreenNode]) combinator.Parser[[]*GreenNode] {
+	return combinator.Map(combinator.Opt(part), func(p *[]*GreenNode) []*GreenNode {
+		if p == nil {
+			return nil
+		}
+		return *p
+	})
+}
+
+// SepBy matches zero or more parts separated by sep, keeping the separators
+// in the tree.
+func SepBy(part, sep combinator.Parser[[]*GreenNode]) combinator.Parser[[]*GreenNode] {
+	return Opt(Seq(part, Many(Seq(sep, part))))
+}
+
+func concat(parts [][]*GreenNode) []*GreenNode {
+	var children []*GreenNode
+	for _, p := range parts {
+		children = append(children, p...)
+	}
+	return children
+}
+
+// Parse runs p on input and returns the root of the resulting tree.
+// Fails unless p consumes the whole input; end root parsers with [Lexer.End].
+func Parse(p combinator.Parser[*GreenNode], input string) (*SyntaxNode, error) {
+	r := combinator.Parse(combinator.Left(p, combinator.EOF()), input)
+	if !r.OK {
+		return nil, r.Err
+	}
+	return NewRoot(r.Value), nil
+}
@@ -11,7 +11,7 @@ package combinator
 // Example:
 //
 //	quoted := Between(Char('"'), Char('"'), Many(NoneOf("\"")))
-func Between[O, C, T any](open Parser[O], closing Parser[C], p Parser[T]) Parser[T] {
+func Between[O, C, T, I any](open GParser[O, I], closing GParser[C, I], p GParser[T, I]) GParser[T, I] {
 	return Map(Seq3(open, p, closing), func(t Triple[O, T, C]) T {
 		return t.Second
 	})
@@ -72,8 +72,8 @@ func Angles[T any](p Parser[T]) Parser[T] {
 //	items := SepBy(Integer(), Char(','))
 //	result := Parse(items, "1,2,3")
 //	// result.Value == []int64{1, 2, 3}
-func SepBy[T, S any](p Parser[T], sep Parser[S]) Parser[[]T] {
-	return Choice(SepBy1(p, sep), Map(EOF(), func(_ struct{}) []T { return []T{} }))
+func SepBy[T, S, I any](p GParser[T, I], sep GParser[S, I]) GParser[[]T, I] {
+	return Choice(SepBy1(p, sep), Map(EndOfInput[I](), func(_ struct{}) []T { return []T{} }))
 }
 
 // SepBy1 matches one or more occurrences of a parser separated by a delimiter.
@@ -85,7 +85,7 @@ func SepBy[T, S any](p Parser[T], sep Parser[S]) Parser[[]T] {
 //	items := SepBy1(Ident(), Char(','))
 //	result := Parse(items, "a,b,c")
 //	// result.Value == []string{"a", "b", "c"}
-func SepBy1[T, S any](p Parser[T], sep Parser[S]) Parser[[]T] {
+func SepBy1[T, S, I any](p GParser[T, I], sep GParser[S, I]) GParser[[]T, I] {
 	rest := Many(Right(sep, p))
 
 	return Map(Seq2(p, rest), func(pair Pair[T, []T]) []T {
@@ -101,7 +101,7 @@ func SepBy1[T, S any](p Parser[T], sep Parser[S]) Parser[[]T] {
 //	statements := EndBy(Ident(), Char(';'))
 //	result := Parse(statements, "a;b;c;")
 //	// result.Value == []string{"a", "b", "c"}
-func EndBy[T, S any](p Parser[T], end Parser[S]) Parser[[]T] {
+func EndBy[T, S, I any](p GParser[T, I], end GParser[S, I]) GParser[[]T, I] {
 	return Many(Left(p, end))
 }
 
@@ -113,6 +113,6 @@ func EndBy[T, S any](p Parser[T], end Parser[S]) Parser[[]T] {
 //	statements := EndBy1(Ident(), Char(';'))
 //	result := Parse(statements, "a;b;c;")
 //	// result.Value == []string{"a", "b", "c"}
-func EndBy1[T, S any](p Parser[T], end Parser[S]) Parser[[]T] {
+func EndBy1[T, S, I any](p GParser[T, I], end GParser[S, I]) GParser[[]T, I] {
 	return Many1(Left(p, end))
 }
@@ -0,0 +1,127 @@
+package combinator
+
+import (
+	"errors"
+	"fmt"
+)
+
+// errKind selects the message template of a [parseError].
+type errKind uint8
+
+const (
+	errUnexpectedEOF   errKind = iota // unexpected EOF at ...
+	errExpectedAtEOF                  // unexpected EOF, expected 'x' at ...
+	errMismatch                       // expected 'x', got 'y' at ...
+	errExpected                       // expected 'x' at ...
+	errUnexpected                     // unexpected 'y' at ...
+	errExpectedEOF                    // expected EOF, got 'y' at ...
+	errUnexpectedMatch                // unexpected match at ...
+	errNoAlternatives                 // no alternatives matched at ...
+	errExpectedOneOf                  // expected one of 'x', 'y' at ...
+	errReserved                       // unexpected reserved word 'x' at ...
+)
+
+// parseError is the error produced by the built-in parsers.
+// The message is only formatted when Error is called, so failures that are
+// discarded by backtracking (e.g. inside [Choice] or [Many]) stay cheap.
+type parseError struct {
+	kind errKind
+	want string // want is the expected text for the templates that mention it.
+	got  rune   // got is the rune found in text input.
+	item any    // item is the element found in other input, formatted with %v.
+	line int
+	col  int
+	file string // file is the source name, appended to the message when set.
+}
+
+// errDiscarded stands in for every failure of a quiet parse, whose errors are
+// never reported, to avoid allocating them.
+var errDiscarded = errors.New("parse failed")
+
+// newError builds a positioned error for the template kind at state.
+func newError[I any](kind errKind, want string, state GState[I]) error {
+	if state.session != nil && state.session.quiet {
+		return errDiscarded
+	}
+	e := &parseError{kind: kind, want: want, line: state.Line, col: state.Col, file: state.Source()}
+	switch got := any(state.Current()).(type) {
+	case rune:
+		e.got = got
+	case byte:
+		e.got = rune(got)
+	default:
+		e.item = got
+	}
+	return e
+}
+
+func (e *parseError) Error() string {
+	if e.file != "" {
+		return e.message() + " in " + e.file
+	}
+	return e.message()
+}
+
+func (e *parseError) message() string {
+	switch e.kind {
+	case errExpectedAtEOF:
+		return fmt.Sprintf("unexpected EOF, expected '%s' at line %d, col %d", e.want, e.line, e.col)
+	case errMismatch:
+		return fmt.Sprintf("expected '%s', got '%s' at line %d, col %d", e.want, e.found(), e.line, e.col)
+	case errExpected:
+		return fmt.Sprintf("expected '%s' at line %d, col %d", e.want, e.line, e.col)
+	case errUnexpected:
+		return fmt.Sprintf("unexpected '%s' at line %d, col %d", e.found(), e.line, e.col)
+	case errExpectedEOF:
+		return fmt.Sprintf("expected EOF, got '%s' at line %d, col %d", e.found(), e.line, e.col)
+	case errUnexpectedMatch:
+		return fmt.Sprintf("unexpected match at line %d, col %d", e.line, e.col)
+	case errNoAlternatives:
+		return fmt.Sprintf("no alternatives matched at line %d, col %d", e.line, e.col)
+	case errExpectedOneOf:
+		return fmt.Sprintf("expected one of %s at line %d, col %d", e.want, e.line, e.col)
+	case errReserved:
+		return fmt.Sprintf("unexpected reserved word '%s' at line %d, col %d", e.want, e.line, e.col)
+	default:
+		return fmt.Sprintf("unexpected EOF at line %d, col %d", e.line, e.col)
+	}
+}
+
+// found formats the element the error was found at.
+func (e *parseError) found() string {
+	if e.item != nil {
+		return fmt.Sprint(e.item)
+	}
+	return string(e.got)
+}
+
+// labelError wraps an error with the name given to [Label].
+type labelError struct {
+	label string
+	err   error
+}
+
+func (e *labelError) Error() string {
+	return "expected " + e.label + ": " + e.err.Error()
+}
+
+func (e *labelError) Unwrap() error {
+	return e.err
+}
+
+// ErrorPosition returns the line and column where an error produced by the
+// parsers of this package occurred, looking through wrapping such as [Label].
+// Reports false for other errors, like those returned by [MapErr] callbacks or
+// limit violations.
+//
+// Example:
+//
+//	result := Parse(Char('a'), "xb")
+//	line, col, ok := ErrorPosition(result.Err) // 1, 1, true
+func ErrorPosition(err error) (line, col int, ok bool) {
+	var pe *parseError
+	if errors.As(err, &pe) {
+		return pe.line, pe.col, true
+	}
+	return 0, 0, false
+}
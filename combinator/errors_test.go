@@ -0,0 +1,32 @@
+package combinator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//nolint:paralleltest // tests share parser state
+func TestErrorPosition(t *testing.T) {
+	t.Run("should return the position of parse errors", func(t *testing.T) {
+		result := Parse(Seq2(Char('a'), Char('b')), "a\nc")
+		line, col, ok := ErrorPosition(result.Err)
+		assert.True(t, ok)
+		assert.Equal(t, 1, line)
+		assert.Equal(t, 2, col)
+	})
+
+	t.Run("should look through labels", func(t *testing.T) {
+		result := Parse(Right(String("ab\n"), Label(Digit(), "digit")), "ab\nx")
+		line, col, ok := ErrorPosition(result.Err)
+		assert.True(t, ok)
+		assert.Equal(t, 2, line)
+		assert.Equal(t, 1, col)
+	})
+
+	t.Run("should report false for other errors", func(t *testing.T) {
+		_, _, ok := ErrorPosition(errors.New("custom"))
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,182 @@
+package combinator
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+)
+
+// ErrCommitted is reported when a parse backtracks past events that were
+// already delivered by [Commit]. Use [errors.Is] to detect it.
+var ErrCommitted = errors.New("backtracked past committed events")
+
+// errStopped aborts a parse whose event consumer stopped early.
+var errStopped = errors.New("event consumer stopped")
+
+// EventKind classifies a parse [Event].
+type EventKind uint8
+
+const (
+	// EventStart marks the beginning of a construct wrapped in [Emit].
+	EventStart EventKind = iota
+	// EventEnd marks the end of a construct wrapped in [Emit].
+	EventEnd
+	// EventValue carries the value of a parser wrapped in [EmitValue].
+	EventValue
+)
+
+// Event is a SAX-style notification produced while parsing.
+type Event struct {
+	Kind  EventKind
+	Name  string // Name is the name given to [Emit] or [EmitValue].
+	Value any    // Value is the parsed value of an [EventValue] event.
+	Pos   int    // Pos is the rune offset where the construct starts or ends.
+	Line  int    // Line is the line of Pos (1-indexed).
+	Col   int    // Col is the column of Pos (1-indexed).
+}
+
+// WithEvents delivers the events of [Emit] and [EmitValue] parsers to handler as
+// they are committed. Events are held back while backtracking could still undo
+// them; [Commit] releases them early, and the rest are released when the parse
+// succeeds. Returning false from handler stops the parse.
+//
+// Example:
+//
+//	rows := SkipMany(Commit(EmitValue("row", row)))
+//	Parse(rows, input, WithEvents(func(e Event) bool {
+//		process(e.Value.(Row))
+//		return true
+//	}))
+func WithEvents(handler func(Event) bool) Option {
+	return func(s *session) {
+		s.sink = handler
+	}
+}
+
+// Events runs a parser and yields its committed events as an iterator, without
+// materializing results of the parsers that produced them. If the parse fails,
+// the last pair yielded carries the error. Breaking out of the loop stops the parse.
+//
+// Example:
+//
+//	for e, err := range Events(SkipMany(Commit(EmitValue("row", row))), input) {
+//		if err != nil {
+//			return err
+//		}
+//		process(e.Value.(Row))
+//	}
+func Events[T any](p Parser[T], input string, opts ...Option) iter.Seq2[Event, error] {
+	return func(yield func(Event, error) bool) {
+		stopped := false
+		handler := func(e Event) bool {
+			if !yield(e, nil) {
+				stopped = true
+			}
+			return !stopped
+		}
+
+		r := Parse(p, input, append(opts, WithEvents(handler))...)
+		if !r.OK && !stopped {
+			yield(Event{}, r.Err)
+		}
+	}
+}
+
+// Emit wraps a parser in [EventStart] and [EventEnd] events named name.
+// Events emitted by p appear between them. Without [WithEvents], Emit only runs p.
+//
+// Example:
+//
+//	object := Emit("object", Braces(SepBy(member, Symbol(","))))
+func Emit[T, I any](name string, p GParser[T, I]) GParser[T, I] {
+	return func(state GState[I]) GResult[T, I] {
+		if !state.emitting() {
+			return p(state)
+		}
+
+		r := p(state.emit(state.event(EventStart, name, nil)))
+		if !r.OK {
+			return Failure[T](r.Err, r.State)
+		}
+		return Success(r.Value, r.State.emit(r.State.event(EventEnd, name, nil)))
+	}
+}
+
+// EmitValue emits an [EventValue] event named name carrying the value of p when it succeeds.
+// Without [WithEvents], EmitValue only runs p.
+//
+// Example:
+//
+//	number := EmitValue("number", Integer())
+func EmitValue[T, I any](name string, p GParser[T, I]) GParser[T, I] {
+	return func(state GState[I]) GResult[T, I] {
+		r := p(state)
+		if !r.OK || !state.emitting() {
+			return r
+		}
+
+		// The event is reported at the position where the value started.
+		return Success(r.Value, r.State.emit(state.event(EventValue, name, r.Value)))
+	}
+}
+
+// Commit runs p and, when it succeeds, delivers all events emitted so far to the
+// [WithEvents] handler instead of waiting for the whole parse to succeed.
+// Use it where the grammar will never backtrack, such as after each complete
+// record; backtracking past a commit later fails the parse with [ErrCommitted].
+//
+// Example:
+//
+//	records := SkipMany(Commit(Emit("record", record)))
+func Commit[T, I any](p GParser[T, I]) GParser[T, I] {
+	return func(state GState[I]) GResult[T, I] {
+		r := p(state)
+		if r.OK && state.emitting() {
+			r.State.flush()
+		}
+		return r
+	}
+}
+
+// emitting reports whether events are being collected for this parse.
+func (s GState[I]) emitting() bool {
+	return s.session != nil && s.session.sink != nil && s.session.err == nil
+}
+
+// event returns an event positioned at s.
+func (s GState[I]) event(kind EventKind, name string, value any) Event {
+	return Event{Kind: kind, Name: name, Value: value, Pos: s.Pos, Line: s.Line, Col: s.Col}
+}
+
+// emit records e at s and returns the state following it. Events recorded by
+// alternatives that were abandoned since s are discarded first.
+func (s GState[I]) emit(e Event) GState[I] {
+	ss := s.session
+	if s.events < ss.flushed {
+		ss.abort(fmt.Errorf("%w %s", ErrCommitted, s.at()))
+		return s
+	}
+
+	ss.pending = append(ss.pending[:s.events-ss.flushed], e)
+	s.events++
+	return s
+}
+
+// flush delivers the events leading to s and forgets them.
+func (s GState[I]) flush() {
+	ss := s.session
+	if s.events < ss.flushed {
+		ss.abort(fmt.Errorf("%w %s", ErrCommitted, s.at()))
+		return
+	}
+
+	n := s.events - ss.flushed
+	for _, e := range ss.pending[:n] {
+		if !ss.sink(e) {
+			ss.abort(errStopped)
+			break
+		}
+	}
+	ss.pending = append(ss.pending[:0], ss.pending[n:]...)
+	ss.flushed = s.events
+}
@@ -0,0 +1,140 @@
+package combinator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// records parses lines of comma-separated integers, emitting an event per value
+// and committing after each line.
+func records() Parser[struct{}] {
+	value := EmitValue("value", Integer())
+	line := Emit("record", Left(SepBy1(value, Char(',')), Newline()))
+	return Left(SkipMany(Commit(line)), EOF())
+}
+
+func collect(p Parser[struct{}], input string) ([]Event, error) {
+	var events []Event
+	for e, err := range Events(p, input) {
+		if err != nil {
+			return events, err
+		}
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+//nolint:paralleltest // tests share parser state
+func TestEvents(t *testing.T) {
+	t.Run("should emit start, value and end events in order", func(t *testing.T) {
+		events, err := collect(records(), "1,2\n3\n")
+		require.NoError(t, err)
+
+		var kinds []EventKind
+		var values []any
+		for _, e := range events {
+			kinds = append(kinds, e.Kind)
+			if e.Kind == EventValue {
+				values = append(values, e.Value)
+			}
+		}
+		assert.Equal(t, []EventKind{
+			EventStart, EventValue, EventValue, EventEnd,
+			EventStart, EventValue, EventEnd,
+		}, kinds)
+		assert.Equal(t, []any{int64(1), int64(2), int64(3)}, values)
+	})
+
+	t.Run("should report event positions", func(t *testing.T) {
+		events, err := collect(records(), "1,22\n")
+		require.NoError(t, err)
+		require.Len(t, events, 4)
+
+		assert.Equal(t, Event{Kind: EventStart, Name: "record", Pos: 0, Line: 1, Col: 1}, events[0])
+		assert.Equal(t, Event{Kind: EventValue, Name: "value", Value: int64(22), Pos: 2, Line: 1, Col: 3}, events[2])
+		assert.Equal(t, Event{Kind: EventEnd, Name: "record", Pos: 5, Line: 2, Col: 1}, events[3])
+	})
+
+	t.Run("should discard events of abandoned alternatives", func(t *testing.T) {
+		p := Choice(
+			Skip(Seq2(EmitValue("a", Char('a')), Char('x'))),
+			Skip(Seq2(EmitValue("a", Char('a')), EmitValue("b", Char('b')))),
+		)
+		var names []string
+		for e, err := range Events(p, "ab") {
+			require.NoError(t, err)
+			names = append(names, e.Name)
+		}
+		assert.Equal(t, []string{"a", "b"}, names)
+	})
+
+	t.Run("should keep the events of the longest alternative", func(t *testing.T) {
+		p := Longest(
+			Recognize(EmitValue("long", String("abc"))),
+			Recognize(EmitValue("short", String("ab"))),
+		)
+		var names []string
+		for e, err := range Events(p, "abc") {
+			require.NoError(t, err)
+			names = append(names, e.Name)
+		}
+		assert.Equal(t, []string{"long"}, names)
+	})
+
+	t.Run("should deliver committed events before a later failure", func(t *testing.T) {
+		events, err := collect(records(), "1\n2\nx\n")
+		require.Error(t, err)
+		assert.Len(t, events, 6)
+	})
+
+	t.Run("should withhold uncommitted events on failure", func(t *testing.T) {
+		p := Skip(Seq2(EmitValue("n", Integer()), Char(';')))
+		events, err := collect(p, "42")
+		require.Error(t, err)
+		assert.Empty(t, events)
+	})
+
+	t.Run("should fail when backtracking past committed events", func(t *testing.T) {
+		p := Choice(
+			Skip(Seq2(Commit(EmitValue("a", Char('a'))), Char('x'))),
+			Skip(EmitValue("ab", String("ab"))),
+		)
+		_, err := collect(p, "ab")
+		require.ErrorIs(t, err, ErrCommitted)
+	})
+
+	t.Run("should stop the parse when the consumer breaks", func(t *testing.T) {
+		input := strings.Repeat("1\n", 1000)
+		steps := 0
+		for range Events(records(), input) {
+			steps++
+			if steps == 3 {
+				break
+			}
+		}
+		assert.Equal(t, 3, steps)
+	})
+}
+
+//nolint:paralleltest // tests share parser state
+func TestWithEvents(t *testing.T) {
+	t.Run("should deliver events to the handler", func(t *testing.T) {
+		var sum int64
+		result := Parse(records(), "1,2\n3\n", WithEvents(func(e Event) bool {
+			if e.Kind == EventValue {
+				sum += e.Value.(int64)
+			}
+			return true
+		}))
+		require.True(t, result.OK)
+		assert.Equal(t, int64(6), sum)
+	})
+
+	t.Run("should not emit without a handler", func(t *testing.T) {
+		result := Parse(records(), "1,2\n3\n")
+		require.True(t, result.OK)
+	})
+}
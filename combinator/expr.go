@@ -82,8 +82,8 @@ func StringToken() Parser[string] {
 //	expr := ChainL1(Integer(), addOp)
 //	result := Parse(expr, "1+2+3")
 //	// result.Value == int64(6), computed as ((1+2)+3)
-func ChainL1[T any](p Parser[T], op Parser[func(T, T) T]) Parser[T] {
-	return func(state State) Result[T] {
+func ChainL1[T, I any](p GParser[T, I], op GParser[func(T, T) T, I]) GParser[T, I] {
+	return func(state GState[I]) GResult[T, I] {
 		r := p(state)
 		if !r.OK {
 			return r
@@ -93,6 +93,10 @@ func ChainL1[T any](p Parser[T], op Parser[func(T, T) T]) Parser[T] {
 		current := r.State
 
 		for {
+			if err := current.checkpoint(); err != nil {
+				return Failure[T](err, current)
+			}
+
 			opResult := op(current)
 			if !opResult.OK {
 				break
@@ -125,8 +129,8 @@ func ChainL1[T any](p Parser[T], op Parser[func(T, T) T]) Parser[T] {
 //		return func(a, b float64) float64 { return math.Pow(a, b) }
 //	})
 //	expr := ChainR1(Float(), powOp)
-func ChainR1[T any](p Parser[T], op Parser[func(T, T) T]) Parser[T] {
-	return func(state State) Result[T] {
+func ChainR1[T, I any](p GParser[T, I], op GParser[func(T, T) T, I]) GParser[T, I] {
+	return func(state GState[I]) GResult[T, I] {
 		r := p(state)
 		if !r.OK {
 			return r
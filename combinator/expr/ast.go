@@ -0,0 +1,147 @@
+// Package expr parses and evaluates small expression languages, such as the
+// filter expressions of command-line tools, using the combinator package.
+//
+// [Parse] turns source text into a typed syntax tree whose nodes carry their
+// source spans, and [Eval] computes its value against an [Env] of variables
+// and functions supplied by the caller.
+//
+//	env := &expr.Env{
+//		Vars:  map[string]any{"user": map[string]any{"age": 42, "name": "ada"}},
+//		Funcs: map[string]expr.Func{"len": length},
+//	}
+//	ok, err := expr.Evaluate(`user.age >= 18 && len(user.name) > 2`, env)
+//	// ok == true
+//
+// # Syntax
+//
+// From lowest to highest precedence:
+//
+//	a || b    a or b
+//	a && b    a and b
+//	a == b    a != b
+//	a < b     a <= b    a > b    a >= b
+//	a + b     a - b
+//	a * b     a / b     a % b
+//	-a        !a        not a
+//	f(a, b)   a[i]      a.name
+//
+// Literals are numbers (3, 2.5), double-quoted strings with escapes, true,
+// false and null. Binary operators are left-associative.
+package expr
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/dottermi/x/combinator"
+)
+
+// Span is the source range [Start, End) of a node.
+type Span struct {
+	Start combinator.Position
+	End   combinator.Position
+}
+
+// Node is an expression in the syntax tree.
+// The concrete types are [*Literal], [*Ident], [*Unary], [*Binary], [*Call] and [*Index].
+type Node interface {
+	// Span returns the source range of the node.
+	Span() Span
+	// String formats the node as fully parenthesized source text.
+	String() string
+
+	node()
+}
+
+// Literal is a constant: a float64 number, a string, a bool or nil for null.
+type Literal struct {
+	Value any
+	Loc   Span
+}
+
+// Ident is a reference to a variable or function.
+type Ident struct {
+	Name string
+	Loc  Span
+}
+
+// Unary is a prefix operation: "-" or "!".
+type Unary struct {
+	Op  string
+	X   Node
+	Loc Span
+}
+
+// Binary is an infix operation. Op is one of || && == != < <= > >= + - * / %.
+type Binary struct {
+	Op  string
+	X   Node
+	Y   Node
+	Loc Span
+}
+
+// Call is a function call. Func is usually an [*Ident] naming a function of the [Env].
+type Call struct {
+	Func Node
+	Args []Node
+	Loc  Span
+}
+
+// Index is an element access: X[Index], or X.name with a string [*Literal] index.
+type Index struct {
+	X     Node
+	Index Node
+	Loc   Span
+}
+
+func (n *Literal) Span() Span { return n.Loc }
+func (n *Ident) Span() Span   { return n.Loc }
+func (n *Unary) Span() Span   { return n.Loc }
+func (n *Binary) Span() Span  { return n.Loc }
+func (n *Call) Span() Span    { return n.Loc }
+func (n *Index) Span() Span   { return n.Loc }
+
+func (*Literal) node() {}
+func (*Ident) node()   {}
+func (*Unary) node()   {}
+func (*Binary) node()  {}
+func (*Call) node()    {}
+func (*Index) node()   {}
+
+func (n *Literal) String() string {
+	switch v := n.Value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return strconv.Quote(v)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return "?"
+}
+
+func (n *Ident) String() string {
+	return n.Name
+}
+
+func (n *Unary) String() string {
+	return "(" + n.Op + n.X.String() + ")"
+}
+
+func (n *Binary) String() string {
+	return "(" + n.X.String() + " " + n.Op + " " + n.Y.String() + ")"
+}
+
+func (n *Call) String() string {
+	args := make([]string, len(n.Args))
+	for i, a := range n.Args {
+		args[i] = a.String()
+	}
+	return n.Func.String() + "(" + strings.Join(args, ", ") + ")"
+}
+
+func (n *Index) String() string {
+	return n.X.String() + "[" + n.Index.String() + "]"
+}
@@ -0,0 +1,339 @@
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Sentinel errors wrapped by [*Error].
+var (
+	ErrUndefined   = errors.New("undefined")
+	ErrType        = errors.New("type mismatch")
+	ErrNotCallable = errors.New("not callable")
+	ErrIndex       = errors.New("index out of range")
+	ErrDivByZero   = errors.New("division by zero")
+)
+
+// Func is a function callable from expressions. Arguments are evaluated
+// values: float64, string, bool, nil, []any or map[string]any.
+type Func func(args ...any) (any, error)
+
+// Env supplies the variables and functions of an evaluation.
+// Integer and float32 variables are read as float64; nested []any and
+// map[string]any values can be indexed.
+type Env struct {
+	Vars  map[string]any
+	Funcs map[string]Func
+}
+
+// Error is an evaluation failure with the span of the node that caused it.
+type Error struct {
+	Span Span
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%v at line %d, col %d", e.Err, e.Span.Start.Line, e.Span.Start.Col)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Evaluate parses src and evaluates it in env.
+//
+// Example:
+//
+//	v, err := Evaluate("1 + 2 * 3", nil)
+//	// v == 7.0
+func Evaluate(src string, env *Env) (any, error) {
+	n, err := Parse(src)
+	if err != nil {
+		return nil, err
+	}
+	return Eval(n, env)
+}
+
+// Eval computes the value of n in env, which may be nil.
+// The result is a float64, string, bool, nil, or a value taken from env.
+//
+// && and || evaluate their right operand only when needed and require bools.
+// + adds numbers or concatenates strings; the other arithmetic operators take
+// numbers. Comparisons take two numbers or two strings, and == and != compare
+// any numbers, strings, bools and nulls. Failures are reported as [*Error].
+//
+// Example:
+//
+//	n, _ := Parse(`name == "ada" && age >= 18`)
+//	v, err := Eval(n, &Env{Vars: map[string]any{"name": "ada", "age": 36}})
+//	// v == true
+func Eval(n Node, env *Env) (any, error) {
+	if env == nil {
+		env = &Env{}
+	}
+	return env.eval(n)
+}
+
+func (env *Env) eval(n Node) (any, error) {
+	switch n := n.(type) {
+	case *Literal:
+		return n.Value, nil
+	case *Ident:
+		v, ok := env.Vars[n.Name]
+		if !ok {
+			return nil, fail(n, "%w variable %q", ErrUndefined, n.Name)
+		}
+		return normalize(v), nil
+	case *Unary:
+		return env.unary(n)
+	case *Binary:
+		return env.binary(n)
+	case *Call:
+		return env.call(n)
+	case *Index:
+		return env.index(n)
+	}
+	return nil, fail(n, "%w: unknown node %T", ErrType, n)
+}
+
+func (env *Env) unary(n *Unary) (any, error) {
+	x, err := env.eval(n.X)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.Op {
+	case "-":
+		if f, ok := x.(float64); ok {
+			return -f, nil
+		}
+	case "!":
+		if b, ok := x.(bool); ok {
+			return !b, nil
+		}
+	}
+	return nil, fail(n, "%w: %s%s", ErrType, n.Op, typeName(x))
+}
+
+func (env *Env) binary(n *Binary) (any, error) {
+	x, err := env.eval(n.X)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.Op == "&&" || n.Op == "||" {
+		left, ok := x.(bool)
+		if !ok {
+			return nil, fail(n.X, "%w: %s %s", ErrType, typeName(x), n.Op)
+		}
+		if left == (n.Op == "||") {
+			return left, nil
+		}
+		y, err := env.eval(n.Y)
+		if err != nil {
+			return nil, err
+		}
+		right, ok := y.(bool)
+		if !ok {
+			return nil, fail(n.Y, "%w: %s %s", ErrType, n.Op, typeName(y))
+		}
+		return right, nil
+	}
+
+	y, err := env.eval(n.Y)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.Op {
+	case "==", "!=":
+		if !equatable(x) || !equatable(y) {
+			break
+		}
+		return (x == y) == (n.Op == "=="), nil
+	case "+":
+		if a, ok := x.(string); ok {
+			if b, ok := y.(string); ok {
+				return a + b, nil
+			}
+		}
+	case "<", "<=", ">", ">=":
+		if a, ok := x.(string); ok {
+			if b, ok := y.(string); ok {
+				return compare(n.Op, a, b), nil
+			}
+		}
+	}
+
+	a, ok1 := x.(float64)
+	b, ok2 := y.(float64)
+	if !ok1 || !ok2 {
+		return nil, fail(n, "%w: %s %s %s", ErrType, typeName(x), n.Op, typeName(y))
+	}
+
+	switch n.Op {
+	case "+":
+		return a + b, nil
+	case "-":
+		return a - b, nil
+	case "*":
+		return a * b, nil
+	case "/":
+		if b == 0 {
+			return nil, fail(n, "%w", ErrDivByZero)
+		}
+		return a / b, nil
+	case "%":
+		if b == 0 {
+			return nil, fail(n, "%w", ErrDivByZero)
+		}
+		return math.Mod(a, b), nil
+	case "<", "<=", ">", ">=":
+		return compare(n.Op, a, b), nil
+	}
+	return nil, fail(n, "%w: unknown operator %q", ErrType, n.Op)
+}
+
+func (env *Env) call(n *Call) (any, error) {
+	ident, ok := n.Func.(*Ident)
+	if !ok {
+		return nil, fail(n.Func, "%w: %s", ErrNotCallable, n.Func)
+	}
+	f, ok := env.Funcs[ident.Name]
+	if !ok {
+		return nil, fail(ident, "%w function %q", ErrUndefined, ident.Name)
+	}
+
+	args := make([]any, len(n.Args))
+	for i, arg := range n.Args {
+		v, err := env.eval(arg)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+
+	v, err := f(args...)
+	if err != nil {
+		return nil, &Error{Span: n.Loc, Err: fmt.Errorf("%s: %w", ident.Name, err)}
+	}
+	return normalize(v), nil
+}
+
+func (env *Env) index(n *Index) (any, error) {
+	x, err := env.eval(n.X)
+	if err != nil {
+		return nil, err
+	}
+	i, err := env.eval(n.Index)
+	if err != nil {
+		return nil, err
+	}
+
+	switch x := x.(type) {
+	case []any:
+		f, ok := i.(float64)
+		if !ok || f != math.Trunc(f) {
+			return nil, fail(n.Index, "%w: list index %s", ErrType, typeName(i))
+		}
+		// Compare as floats: huge indexes do not survive the conversion to int.
+		if f < 0 || f >= float64(len(x)) {
+			return nil, fail(n.Index, "%w: %v with length %d", ErrIndex, f, len(x))
+		}
+		return normalize(x[int(f)]), nil
+	case map[string]any:
+		key, ok := i.(string)
+		if !ok {
+			return nil, fail(n.Index, "%w: map key %s", ErrType, typeName(i))
+		}
+		// Missing keys read as null so filters can test optional fields.
+		return normalize(x[key]), nil
+	case string:
+		f, ok := i.(float64)
+		runes := []rune(x)
+		if !ok || f != math.Trunc(f) {
+			return nil, fail(n.Index, "%w: string index %s", ErrType, typeName(i))
+		}
+		if f < 0 || f >= float64(len(runes)) {
+			return nil, fail(n.Index, "%w: %v with length %d", ErrIndex, f, len(runes))
+		}
+		return string(runes[int(f)]), nil
+	}
+	return nil, fail(n.X, "%w: cannot index %s", ErrType, typeName(x))
+}
+
+// fail returns an [*Error] at the span of n.
+func fail(n Node, format string, args ...any) error {
+	return &Error{Span: n.Span(), Err: fmt.Errorf(format, args...)}
+}
+
+// compare applies an ordering operator.
+func compare[T float64 | string](op string, a, b T) bool {
+	switch op {
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	}
+	return a >= b
+}
+
+// equatable reports whether v can be compared with == and !=.
+func equatable(v any) bool {
+	switch v.(type) {
+	case nil, float64, string, bool:
+		return true
+	}
+	return false
+}
+
+// normalize converts Go numbers to float64, the only number type of expressions.
+func normalize(v any) any {
+	switch v := v.(type) {
+	case int:
+		return float64(v)
+	case int8:
+		return float64(v)
+	case int16:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint:
+		return float64(v)
+	case uint8:
+		return float64(v)
+	case uint16:
+		return float64(v)
+	case uint32:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	}
+	return v
+}
+
+// typeName names the type of a value in error messages.
+func typeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case []any:
+		return "list"
+	case map[string]any:
+		return "map"
+	}
+	return fmt.Sprintf("%T", v)
+}
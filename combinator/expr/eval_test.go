@@ -0,0 +1,127 @@
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testEnv() *Env {
+	return &Env{
+		Vars: map[string]any{
+			"age":  36,
+			"name": "ada",
+			"tags": []any{"admin", "ops"},
+			"user": map[string]any{"email": "ada@example.com", "score": float32(1.5)},
+		},
+		Funcs: map[string]Func{
+			"len": func(args ...any) (any, error) {
+				if len(args) != 1 {
+					return nil, fmt.Errorf("want 1 argument, got %d", len(args))
+				}
+				switch v := args[0].(type) {
+				case string:
+					return len([]rune(v)), nil
+				case []any:
+					return len(v), nil
+				}
+				return nil, errors.New("want a string or list")
+			},
+			"upper": func(args ...any) (any, error) {
+				return strings.ToUpper(fmt.Sprint(args...)), nil
+			},
+		},
+	}
+}
+
+func TestEval(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]any{
+		"1 + 2 * 3":                       7.0,
+		"(1 + 2) * 3":                     9.0,
+		"7 % 4 - -1":                      4.0,
+		"10 / 4":                          2.5,
+		"age >= 18 && name == \"ada\"":    true,
+		"not (age < 18)":                  true,
+		`"a" + "b" < "b"`:                 true,
+		"tags[1]":                         "ops",
+		"user.score * 2":                  3.0,
+		"user.missing == null":            true,
+		"len(tags) + len(name)":           5.0,
+		"upper(name)":                     "ADA",
+		"name[0]":                         "a",
+		"false && undefined":              false,
+		"true || undefined":               true,
+		"len(user.email) > 3 or age == 0": true,
+		"1 == \"1\"":                      false,
+	}
+
+	for src, want := range cases {
+		v, err := Evaluate(src, testEnv())
+		require.NoError(t, err, "src %q", src)
+		assert.Equal(t, want, v, "src %q", src)
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		src  string
+		want error
+		col  int
+	}{
+		{src: "1 + missing", want: ErrUndefined, col: 5},
+		{src: "nope(1)", want: ErrUndefined, col: 1},
+		{src: "age + name", want: ErrType, col: 1},
+		{src: "-name", want: ErrType, col: 1},
+		{src: "age && true", want: ErrType, col: 1},
+		{src: "tags[5]", want: ErrIndex, col: 6},
+		{src: "tags[0.5]", want: ErrType, col: 6},
+		{src: "tags[1" + strings.Repeat("0", 300) + "]", want: ErrIndex, col: 6}, // 1e300
+		{src: "tags[-0.5]", want: ErrType, col: 6},
+		{src: "name[1" + strings.Repeat("0", 300) + "]", want: ErrIndex, col: 6},
+		{src: "age / (age - 36)", want: ErrDivByZero, col: 1},
+		{src: "tags(1)", want: ErrUndefined, col: 1},
+		{src: "(1)(2)", want: ErrNotCallable, col: 1},
+		{src: "age.x", want: ErrType, col: 1},
+		{src: "tags == tags", want: ErrType, col: 1},
+	}
+
+	for _, tc := range cases {
+		_, err := Evaluate(tc.src, testEnv())
+		require.ErrorIs(t, err, tc.want, "src %q", tc.src)
+
+		var evalErr *Error
+		require.ErrorAs(t, err, &evalErr, "src %q", tc.src)
+		assert.Equal(t, tc.col, evalErr.Span.Start.Col, "src %q", tc.src)
+	}
+
+	t.Run("should wrap function errors with the call span", func(t *testing.T) {
+		t.Parallel()
+		_, err := Evaluate("1 + len(1, 2)", testEnv())
+		require.Error(t, err)
+		assert.Equal(t, "len: want 1 argument, got 2 at line 1, col 5", err.Error())
+	})
+
+	t.Run("should report parse errors", func(t *testing.T) {
+		t.Parallel()
+		_, err := Evaluate("1 +", nil)
+		require.Error(t, err)
+
+		var evalErr *Error
+		assert.NotErrorAs(t, err, &evalErr)
+	})
+
+	t.Run("should evaluate constants without an env", func(t *testing.T) {
+		t.Parallel()
+		v, err := Evaluate("2 * 21", nil)
+		require.NoError(t, err)
+		assert.Equal(t, 42.0, v)
+	})
+}
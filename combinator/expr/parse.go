@@ -0,0 +1,213 @@
+package expr
+
+import (
+	"strconv"
+	"unicode"
+
+	"github.com/dottermi/x/combinator"
+)
+
+// reserved words cannot be used as identifiers.
+var reserved = []string{"true", "false", "null", "and", "or", "not"}
+
+// spanned is a token value with the source range it was read from.
+type spanned[T any] struct {
+	value T
+	span  Span
+}
+
+// Parse parses src as a single expression.
+//
+// Example:
+//
+//	n, err := Parse("price * (1 + tax)")
+//	fmt.Println(n) // (price * (1 + tax))
+func Parse(src string) (Node, error) {
+	r := combinator.Parse(combinator.Left(Parser(), combinator.EOF()), src)
+	if !r.OK {
+		return nil, r.Err
+	}
+	return r.Value, nil
+}
+
+// Parser returns a parser for one expression followed by optional whitespace,
+// for embedding expressions in larger grammars. Leading whitespace is skipped.
+func Parser() combinator.Parser[Node] {
+	var expr combinator.Rule[Node]
+	ref := combinator.Ref(&expr)
+
+	number := combinator.Map(token(unsigned()), func(t spanned[float64]) Node {
+		return &Literal{Value: t.value, Loc: t.span}
+	})
+	str := combinator.Map(token(combinator.StringLit()), func(t spanned[string]) Node {
+		return &Literal{Value: t.value, Loc: t.span}
+	})
+	constant := combinator.Map(token(combinator.Choice(
+		keyword("true"), keyword("false"), keyword("null"),
+	)), func(t spanned[string]) Node {
+		var v any
+		if t.value != "null" {
+			v = t.value == "true"
+		}
+		return &Literal{Value: v, Loc: t.span}
+	})
+	ident := combinator.Map(token(combinator.IdentExcept(reserved...)), func(t spanned[string]) Node {
+		return &Ident{Name: t.value, Loc: t.span}
+	})
+	parens := combinator.Map(combinator.Seq3(symbol("("), ref, symbol(")")),
+		func(t combinator.Triple[spanned[string], Node, spanned[string]]) Node {
+			return widen(t.Second, Span{t.First.span.Start, t.Third.span.End})
+		},
+	)
+
+	primary := combinator.Choice(number, str, constant, ident, parens)
+	operand := postfix(primary, ref)
+
+	var unary combinator.Parser[Node]
+	prefix := combinator.Choice(
+		symbol("-"),
+		symbol("!"),
+		combinator.Map(token(keyword("not")), func(t spanned[string]) spanned[string] {
+			return spanned[string]{value: "!", span: t.span}
+		}),
+	)
+	unary = combinator.Choice(
+		combinator.Map(
+			combinator.Seq2(prefix, combinator.Lazy(func() combinator.Parser[Node] { return unary })),
+			func(p combinator.Pair[spanned[string], Node]) Node {
+				return &Unary{Op: p.First.value, X: p.Second, Loc: Span{p.First.span.Start, p.Second.Span().End}}
+			},
+		),
+		operand,
+	)
+
+	mul := combinator.ChainL1(unary, binary(combinator.OneOfStrings("*", "/", "%")))
+	add := combinator.ChainL1(mul, binary(combinator.OneOfStrings("+", "-")))
+	cmp := combinator.ChainL1(add, binary(combinator.OneOfStrings("<", "<=", ">", ">=")))
+	eq := combinator.ChainL1(cmp, binary(combinator.OneOfStrings("==", "!=")))
+	and := combinator.ChainL1(eq, binary(word("&&", "and")))
+	or := combinator.ChainL1(and, binary(word("||", "or")))
+
+	expr = func() combinator.Parser[Node] { return or }
+	return combinator.Right(combinator.Spaces(), ref)
+}
+
+// postfix parses an operand followed by any number of calls, index
+// expressions and member accesses.
+func postfix(primary, expr combinator.Parser[Node]) combinator.Parser[Node] {
+	args := combinator.Seq2(
+		combinator.Right(symbol("("), combinator.Map(
+			combinator.Opt(combinator.SepBy1(expr, symbol(","))),
+			func(args *[]Node) []Node {
+				if args == nil {
+					return nil
+				}
+				return *args
+			},
+		)),
+		symbol(")"),
+	)
+	index := combinator.Seq2(combinator.Right(symbol("["), expr), symbol("]"))
+	member := combinator.Right(symbol("."), token(combinator.Ident()))
+
+	return func(state combinator.State) combinator.Result[Node] {
+		r := primary(state)
+		if !r.OK {
+			return r
+		}
+		n, current := r.Value, r.State
+
+		for {
+			if a := args(current); a.OK {
+				n = &Call{Func: n, Args: a.Value.First, Loc: Span{n.Span().Start, a.Value.Second.span.End}}
+				current = a.State
+				continue
+			}
+			if i := index(current); i.OK {
+				n = &Index{X: n, Index: i.Value.First, Loc: Span{n.Span().Start, i.Value.Second.span.End}}
+				current = i.State
+				continue
+			}
+			if m := member(current); m.OK {
+				key := &Literal{Value: m.Value.value, Loc: m.Value.span}
+				n = &Index{X: n, Index: key, Loc: Span{n.Span().Start, m.Value.span.End}}
+				current = m.State
+				continue
+			}
+			return combinator.Success(n, current)
+		}
+	}
+}
+
+// widen sets the span of a parenthesized node to include its parentheses.
+func widen(n Node, span Span) Node {
+	switch n := n.(type) {
+	case *Literal:
+		n.Loc = span
+	case *Ident:
+		n.Loc = span
+	case *Unary:
+		n.Loc = span
+	case *Binary:
+		n.Loc = span
+	case *Call:
+		n.Loc = span
+	case *Index:
+		n.Loc = span
+	}
+	return n
+}
+
+// unsigned matches a number without a sign, which is parsed as a [Unary] operator instead.
+func unsigned() combinator.Parser[float64] {
+	digits := combinator.Label(combinator.TakeWhile1(unicode.IsDigit), "number")
+	text := combinator.Recognize(combinator.Seq2(digits, combinator.Opt(combinator.Seq2(combinator.Char('.'), digits))))
+
+	return combinator.Map(text, func(s string) float64 {
+		f, _ := strconv.ParseFloat(s, 64)
+		return f
+	})
+}
+
+// binary turns an operator parser into a combining function for ChainL1.
+func binary(op combinator.Parser[string]) combinator.Parser[func(Node, Node) Node] {
+	return combinator.Map(token(op), func(t spanned[string]) func(Node, Node) Node {
+		return func(x, y Node) Node {
+			return &Binary{Op: t.value, X: x, Y: y, Loc: Span{x.Span().Start, y.Span().End}}
+		}
+	})
+}
+
+// word matches an operator written as a symbol or as a keyword, returning the symbol.
+func word(sym, kw string) combinator.Parser[string] {
+	return combinator.Choice(
+		combinator.String(sym),
+		combinator.Map(keyword(kw), func(string) string { return sym }),
+	)
+}
+
+// keyword matches kw only as a whole identifier. [combinator.Keyword] stops
+// at letters and digits; an underscore must not follow either, so "not_found"
+// and "null_count" are identifiers, as [combinator.IdentExcept] reads them.
+func keyword(kw string) combinator.Parser[string] {
+	return combinator.Left(combinator.Keyword(kw), combinator.Not(combinator.Char('_')))
+}
+
+// symbol matches a literal token.
+func symbol(s string) combinator.Parser[spanned[string]] {
+	return token(combinator.String(s))
+}
+
+// token runs p, records the range it matched and skips the whitespace after it.
+func token[T any](p combinator.Parser[T]) combinator.Parser[spanned[T]] {
+	spaces := combinator.Spaces()
+
+	return func(state combinator.State) combinator.Result[spanned[T]] {
+		r := p(state)
+		if !r.OK {
+			return combinator.Failure[spanned[T]](r.Err, r.State)
+		}
+		span := Span{Start: state.Position(), End: r.State.Position()}
+		return combinator.Success(spanned[T]{value: r.Value, span: span}, spaces(r.State).State)
+	}
+}
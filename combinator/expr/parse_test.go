@@ -0,0 +1,100 @@
+package expr
+
+import (
+	"testing"
+
+	"github.com/dottermi/x/combinator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"1 + 2 * 3":               "(1 + (2 * 3))",
+		"(1 + 2) * 3":             "((1 + 2) * 3)",
+		"10 - 4 - 3":              "((10 - 4) - 3)",
+		"-x * 2":                  "((-x) * 2)",
+		"not a and b or c":        "(((!a) && b) || c)",
+		"!!ok":                    "(!(!ok))",
+		"a < b == c >= d":         "((a < b) == (c >= d))",
+		"a <= 1 && b != null":     "((a <= 1) && (b != null))",
+		`name == "ada"`:           `(name == "ada")`,
+		"max(1, 2.5)":             "max(1, 2.5)",
+		"now()":                   "now()",
+		"users[0].name":           `users[0]["name"]`,
+		"f(x)[1](y)":              "f(x)[1](y)",
+		"  true || false  ":       "(true || false)",
+		"order and notes":         "(order && notes)",
+		"null_count + 1":          "(null_count + 1)",
+		"true_value":              "true_value",
+		"not_found":               "not_found",
+		"and_x && or_y":           "(and_x && or_y)",
+		"not false_":              "(!false_)",
+		"a % 2 == 0 || a / 2 > 3": "(((a % 2) == 0) || ((a / 2) > 3))",
+	}
+
+	for src, want := range cases {
+		n, err := Parse(src)
+		require.NoError(t, err, "src %q", src)
+		assert.Equal(t, want, n.String(), "src %q", src)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, src := range []string{"", "1 +", "f(1,", "a[1", "(a", "true = 1", "and", "1 2", "a or_b", "a and_b"} {
+		_, err := Parse(src)
+		assert.Error(t, err, "src %q", src)
+	}
+}
+
+func TestSpans(t *testing.T) {
+	t.Parallel()
+
+	n, err := Parse("price * (1 + tax)\n  >= limit")
+	require.NoError(t, err)
+
+	cmp, ok := n.(*Binary)
+	require.True(t, ok)
+
+	t.Run("should span the whole expression", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, 0, cmp.Span().Start.Offset)
+		assert.Equal(t, 2, cmp.Span().End.Line)
+		assert.Equal(t, 11, cmp.Span().End.Col)
+	})
+
+	t.Run("should exclude trailing whitespace from tokens", func(t *testing.T) {
+		t.Parallel()
+		mul, ok := cmp.X.(*Binary)
+		require.True(t, ok)
+		assert.Equal(t, 17, mul.Span().End.Offset)
+		assert.Equal(t, 8, mul.Y.Span().Start.Offset, "should include parentheses")
+
+		price := mul.X.Span()
+		assert.Equal(t, 0, price.Start.Offset)
+		assert.Equal(t, 5, price.End.Offset)
+	})
+
+	t.Run("should locate identifiers on later lines", func(t *testing.T) {
+		t.Parallel()
+		limit := cmp.Y.Span().Start
+		assert.Equal(t, 2, limit.Line)
+		assert.Equal(t, 6, limit.Col)
+	})
+}
+
+func TestParser(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should embed in larger grammars", func(t *testing.T) {
+		t.Parallel()
+		rule := combinator.Seq2(combinator.Lexeme(combinator.Keyword("where")), Parser())
+		r := combinator.Parse(rule, "where size > 10")
+		require.True(t, r.OK)
+		assert.Equal(t, "(size > 10)", r.Value.Second.String())
+	})
+}
@@ -0,0 +1,239 @@
+package grammar
+
+import (
+	"strings"
+
+	"github.com/dottermi/x/combinator"
+)
+
+// expr is a node of a rule body as written in the grammar text.
+type expr interface{}
+
+type (
+	// seqExpr matches each element in order.
+	seqExpr []expr
+	// choiceExpr tries each alternative in order.
+	choiceExpr []expr
+	// repeatExpr matches inner between min and max times (max < 0 means unbounded).
+	repeatExpr struct {
+		inner    expr
+		min, max int
+	}
+	// predExpr is a lookahead that never consumes input.
+	predExpr struct {
+		inner expr
+		not   bool
+	}
+	// refExpr invokes another rule.
+	refExpr struct {
+		name      string
+		line, col int
+	}
+	// litExpr matches a literal string.
+	litExpr string
+	// classExpr matches a single rune in (or, when negated, outside) the ranges.
+	classExpr struct {
+		ranges  []runeRange
+		negated bool
+	}
+	// anyExpr matches any single rune.
+	anyExpr struct{}
+)
+
+// runeRange is an inclusive range of runes.
+type runeRange struct {
+	lo, hi rune
+}
+
+func (c classExpr) matches(r rune) bool {
+	for _, rr := range c.ranges {
+		if r >= rr.lo && r <= rr.hi {
+			return !c.negated
+		}
+	}
+	return c.negated
+}
+
+// children returns the sub-expressions of e.
+func children(e expr) []expr {
+	switch e := e.(type) {
+	case seqExpr:
+		return e
+	case choiceExpr:
+		return e
+	case repeatExpr:
+		return []expr{e.inner}
+	case predExpr:
+		return []expr{e.inner}
+	}
+	return nil
+}
+
+// canBeEmpty reports whether e can succeed without consuming input.
+func canBeEmpty(e expr, nullable map[string]bool) bool {
+	switch e := e.(type) {
+	case seqExpr:
+		for _, item := range e {
+			if !canBeEmpty(item, nullable) {
+				return false
+			}
+		}
+		return true
+	case choiceExpr:
+		for _, alt := range e {
+			if canBeEmpty(alt, nullable) {
+				return true
+			}
+		}
+		return false
+	case repeatExpr:
+		return e.min == 0 || canBeEmpty(e.inner, nullable)
+	case predExpr:
+		return true
+	case refExpr:
+		return nullable[e.name]
+	case litExpr:
+		return e == ""
+	}
+	return false
+}
+
+// leftRefs returns the rules e may invoke before consuming any input.
+func leftRefs(e expr, nullable map[string]bool) []string {
+	switch e := e.(type) {
+	case refExpr:
+		return []string{e.name}
+	case seqExpr:
+		var names []string
+		for _, item := range e {
+			names = append(names, leftRefs(item, nullable)...)
+			if !canBeEmpty(item, nullable) {
+				break
+			}
+		}
+		return names
+	case choiceExpr:
+		var names []string
+		for _, alt := range e {
+			names = append(names, leftRefs(alt, nullable)...)
+		}
+		return names
+	case repeatExpr:
+		return leftRefs(e.inner, nullable)
+	case predExpr:
+		return leftRefs(e.inner, nullable)
+	}
+	return nil
+}
+
+// compiler turns rule bodies into parsers collecting child nodes.
+type compiler struct {
+	rules map[string]*combinator.Rule[Node]
+}
+
+// rule wraps a compiled body into a parser producing a node for the named rule.
+func (c *compiler) rule(name string, body combinator.Parser[[]Node]) combinator.Parser[Node] {
+	return func(state combinator.State) combinator.Result[Node] {
+		r := body(state)
+		if !r.OK {
+			return combinator.Failure[Node](r.Err, r.State)
+		}
+
+		node := Node{
+			Rule: name,
+			Text: string(state.Input[state.Pos:r.State.Pos]),
+			Span: Span{
+				Start: Position{Offset: state.Pos, Line: state.Line, Col: state.Col},
+				End:   Position{Offset: r.State.Pos, Line: r.State.Line, Col: r.State.Col},
+			},
+			Children: r.Value,
+		}
+		return combinator.Success(node, r.State)
+	}
+}
+
+func (c *compiler) compile(e expr) combinator.Parser[[]Node] {
+	switch e := e.(type) {
+	case seqExpr:
+		return c.sequence(e)
+	case choiceExpr:
+		alts := make([]combinator.Parser[[]Node], len(e))
+		for i, alt := range e {
+			alts[i] = c.compile(alt)
+		}
+		return combinator.Choice(alts...)
+	case repeatExpr:
+		return c.repeat(e)
+	case predExpr:
+		if e.not {
+			return none(combinator.Not(c.compile(e.inner)))
+		}
+		return none(combinator.LookAhead(c.compile(e.inner)))
+	case refExpr:
+		return c.reference(e.name)
+	case litExpr:
+		return none(combinator.String(string(e)))
+	case classExpr:
+		return none(combinator.Satisfy(e.matches))
+	default:
+		return none(combinator.Any())
+	}
+}
+
+func (c *compiler) sequence(items seqExpr) combinator.Parser[[]Node] {
+	parsers := make([]combinator.Parser[[]Node], len(items))
+	for i, item := range items {
+		parsers[i] = c.compile(item)
+	}
+
+	return func(state combinator.State) combinator.Result[[]Node] {
+		var nodes []Node
+		current := state
+		for _, p := range parsers {
+			r := p(current)
+			if !r.OK {
+				return combinator.Failure[[]Node](r.Err, r.State)
+			}
+			nodes = append(nodes, r.Value...)
+			current = r.State
+		}
+		return combinator.Success(nodes, current)
+	}
+}
+
+func (c *compiler) repeat(e repeatExpr) combinator.Parser[[]Node] {
+	inner := c.compile(e.inner)
+	if e.max == 1 {
+		return combinator.Map(combinator.Opt(inner), func(nodes *[]Node) []Node {
+			if nodes == nil {
+				return nil
+			}
+			return *nodes
+		})
+	}
+
+	many := combinator.Many(inner)
+	if e.min > 0 {
+		many = combinator.Many1(inner)
+	}
+	return combinator.Map(many, func(groups [][]Node) []Node {
+		var nodes []Node
+		for _, g := range groups {
+			nodes = append(nodes, g...)
+		}
+		return nodes
+	})
+}
+
+func (c *compiler) reference(name string) combinator.Parser[[]Node] {
+	ref := combinator.Ref(c.rules[name])
+	if strings.HasPrefix(name, "_") {
+		return combinator.Map(ref, func(n Node) []Node { return n.Children })
+	}
+	return combinator.Map(ref, func(n Node) []Node { return []Node{n} })
+}
+
+// none discards a parser's value, producing no child nodes.
+func none[T any](p combinator.Parser[T]) combinator.Parser[[]Node] {
+	return combinator.Map(p, func(T) []Node { return nil })
+}
@@ -0,0 +1,254 @@
+// Package grammar builds parsers at runtime from PEG or EBNF grammar text.
+//
+// Grammars are loaded with [Load] and turned into a [combinator.Parser] producing
+// a generic parse tree of [Node] values. Every rule application becomes a node
+// carrying the rule name, the matched text, its span, and the nodes of the rules
+// it invoked. This lets grammars live in configuration files instead of Go code.
+//
+// # PEG Syntax
+//
+//	# comments run to the end of the line
+//	List   <- '[' _ Items? ']'
+//	Items  <- Number (',' _ Number)*
+//	Number <- [0-9]+ _
+//	_      <- [ \t\n]*
+//
+// Supported operators: sequence, ordered choice (/), grouping ( ), repetition
+// (* + ?), lookahead (& !), literals ('x' "x"), character classes ([a-z] [^"]),
+// and any character (.).
+//
+// # EBNF Syntax
+//
+//	(* comments are enclosed like this *)
+//	list   = "[" , _ , [ items ] , "]" ;
+//	items  = number , { "," , _ , number } ;
+//	number = "0".."9" , { "0".."9" } , _ ;
+//	_      = { " " | "\t" | "\n" } ;
+//
+// Rules are defined with = or ::= and optionally terminated by ;. Concatenation
+// may be written with or without commas. Supported operators: alternation (|),
+// grouping ( ), optional [ ], repetition { }, postfix * + ?, and character
+// ranges ("a".."z").
+//
+// Alternatives are always tried in order, so EBNF grammars are interpreted with
+// PEG semantics: the first matching alternative wins.
+//
+// # Transparent Rules
+//
+// Rules whose name starts with an underscore do not produce nodes of their own;
+// their children are spliced into the calling rule. Use them for whitespace and
+// other helper rules that would otherwise clutter the tree.
+package grammar
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/dottermi/x/combinator"
+)
+
+// Syntax selects the grammar notation accepted by [Load].
+type Syntax int
+
+const (
+	// PEG is the parsing expression grammar notation with <- definitions.
+	PEG Syntax = iota
+	// EBNF is the extended Backus-Naur form notation with = or ::= definitions.
+	EBNF
+)
+
+// Errors returned by [Load] and [Grammar.Parser].
+var (
+	ErrSyntax        = errors.New("grammar syntax error")
+	ErrUndefinedRule = errors.New("undefined rule")
+	ErrDuplicateRule = errors.New("duplicate rule")
+	ErrLeftRecursion = errors.New("left-recursive rule")
+)
+
+// Position identifies a location in the parsed input.
+type Position struct {
+	Offset int // Offset is the rune offset from the start of the input.
+	Line   int // Line is the line number (1-indexed).
+	Col    int // Col is the column number (1-indexed).
+}
+
+// Span is the half-open input range [Start, End) matched by a node.
+type Span struct {
+	Start Position
+	End   Position
+}
+
+// Node is a parse tree node produced by a rule application.
+type Node struct {
+	Rule     string // Rule is the name of the rule that produced this node.
+	Text     string // Text is the input matched by the rule.
+	Span     Span   // Span is the input range matched by the rule.
+	Children []Node // Children holds nodes produced by rules invoked from this rule.
+}
+
+// Find returns the first direct child produced by the named rule.
+func (n Node) Find(rule string) (Node, bool) {
+	for _, child := range n.Children {
+		if child.Rule == rule {
+			return child, true
+		}
+	}
+	return Node{}, false
+}
+
+// FindAll returns all direct children produced by the named rule.
+func (n Node) FindAll(rule string) []Node {
+	var nodes []Node
+	for _, child := range n.Children {
+		if child.Rule == rule {
+			nodes = append(nodes, child)
+		}
+	}
+	return nodes
+}
+
+// Grammar is a loaded set of rules ready to be turned into parsers.
+type Grammar struct {
+	rules map[string]expr
+	order []string
+}
+
+// Load parses grammar text in the given syntax.
+// Reports syntax errors, references to undefined rules, duplicate definitions,
+// and left recursion, which would otherwise make the parser loop forever.
+//
+// Example:
+//
+//	g, err := grammar.Load(`Greeting <- "hello" " "+ Name
+//	Name <- [a-z]+`, grammar.PEG)
+func Load(src string, syntax Syntax) (*Grammar, error) {
+	defs, err := parseGrammar(src, syntax)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Grammar{rules: make(map[string]expr, len(defs))}
+	for _, d := range defs {
+		if _, ok := g.rules[d.name]; ok {
+			return nil, fmt.Errorf("%w %q at line %d, col %d", ErrDuplicateRule, d.name, d.line, d.col)
+		}
+		g.rules[d.name] = d.body
+		g.order = append(g.order, d.name)
+	}
+
+	if err := g.check(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// Rules returns the rule names in definition order.
+func (g *Grammar) Rules() []string {
+	return slices.Clone(g.order)
+}
+
+// Parser builds a parser starting at the named rule.
+// The parser does not require the whole input to be consumed; combine it with
+// [combinator.EOF] or use [Grammar.Parse] for that.
+func (g *Grammar) Parser(start string) (combinator.Parser[Node], error) {
+	if _, ok := g.rules[start]; !ok {
+		return nil, fmt.Errorf("%w %q", ErrUndefinedRule, start)
+	}
+
+	c := &compiler{rules: make(map[string]*combinator.Rule[Node], len(g.rules))}
+	for _, name := range g.order {
+		c.rules[name] = new(combinator.Rule[Node])
+	}
+	for _, name := range g.order {
+		p := c.rule(name, c.compile(g.rules[name]))
+		*c.rules[name] = func() combinator.Parser[Node] { return p }
+	}
+
+	return combinator.Ref(c.rules[start]), nil
+}
+
+// Parse runs the grammar from the named rule and requires the whole input to match.
+//
+// Example:
+//
+//	tree, err := g.Parse("Greeting", "hello world")
+//	name, _ := tree.Find("Name") // name.Text == "world"
+func (g *Grammar) Parse(start, input string) (Node, error) {
+	p, err := g.Parser(start)
+	if err != nil {
+		return Node{}, err
+	}
+
+	res := combinator.Parse(combinator.Left(p, combinator.EOF()), input)
+	if !res.OK {
+		return Node{}, res.Err
+	}
+	return res.Value, nil
+}
+
+// check validates rule references and rejects left recursion.
+func (g *Grammar) check() error {
+	for _, name := range g.order {
+		if err := g.checkRefs(g.rules[name]); err != nil {
+			return err
+		}
+	}
+
+	nullable := g.nullable()
+	for _, name := range g.order {
+		if g.leftReaches(name, name, nullable, map[string]bool{}) {
+			return fmt.Errorf("%w %q", ErrLeftRecursion, name)
+		}
+	}
+	return nil
+}
+
+func (g *Grammar) checkRefs(e expr) error {
+	if ref, ok := e.(refExpr); ok {
+		if _, defined := g.rules[ref.name]; !defined {
+			return fmt.Errorf("%w %q at line %d, col %d", ErrUndefinedRule, ref.name, ref.line, ref.col)
+		}
+	}
+	for _, child := range children(e) {
+		if err := g.checkRefs(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nullable computes which rules can succeed without consuming input.
+func (g *Grammar) nullable() map[string]bool {
+	result := make(map[string]bool, len(g.rules))
+	for changed := true; changed; {
+		changed = false
+		for _, name := range g.order {
+			if !result[name] && canBeEmpty(g.rules[name], result) {
+				result[name] = true
+				changed = true
+			}
+		}
+	}
+	return result
+}
+
+// leftReaches reports whether rule from can invoke rule target before consuming input.
+func (g *Grammar) leftReaches(from, target string, nullable, visited map[string]bool) bool {
+	if visited[from] {
+		return false
+	}
+	visited[from] = true
+
+	for _, name := range leftRefs(g.rules[from], nullable) {
+		if name == target || g.leftReaches(name, target, nullable, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// syntaxError marks an error from the grammar text parser.
+func syntaxError(err error) error {
+	return fmt.Errorf("%w: %w", ErrSyntax, err)
+}
@@ -0,0 +1,218 @@
+package grammar
+
+import (
+	"testing"
+
+	"github.com/dottermi/x/combinator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const listPEG = `
+# a bracketed list of numbers
+List   <- '[' _ Items? ']'
+Items  <- Number (',' _ Number)*
+Number <- [0-9]+ _
+_      <- [ \t\n]*
+`
+
+const listEBNF = `
+(* a bracketed list of numbers *)
+list   = "[" , _ , [ items ] , "]" ;
+items  = number , { "," , _ , number } ;
+number ::= "0".."9" , { "0".."9" } , _ ;
+_      = { " " | "\t" | "\n" } ;
+`
+
+//nolint:paralleltest // tests share parser state
+func TestLoad(t *testing.T) {
+	t.Run("should load PEG grammar", func(t *testing.T) {
+		g, err := Load(listPEG, PEG)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"List", "Items", "Number", "_"}, g.Rules())
+	})
+
+	t.Run("should load EBNF grammar", func(t *testing.T) {
+		g, err := Load(listEBNF, EBNF)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"list", "items", "number", "_"}, g.Rules())
+	})
+
+	t.Run("should report syntax errors with position", func(t *testing.T) {
+		_, err := Load("A <- 'x'\nB <- (", PEG)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrSyntax)
+		assert.Contains(t, err.Error(), "line 2")
+	})
+
+	t.Run("should reject empty grammar", func(t *testing.T) {
+		_, err := Load("# nothing here", PEG)
+		assert.ErrorIs(t, err, ErrSyntax)
+	})
+
+	t.Run("should reject undefined rules", func(t *testing.T) {
+		_, err := Load("A <- B", PEG)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrUndefinedRule)
+		assert.Contains(t, err.Error(), `"B"`)
+	})
+
+	t.Run("should reject duplicate rules", func(t *testing.T) {
+		_, err := Load("A <- 'a'\nA <- 'b'", PEG)
+		assert.ErrorIs(t, err, ErrDuplicateRule)
+	})
+
+	t.Run("should reject direct left recursion", func(t *testing.T) {
+		_, err := Load("Expr <- Expr '+' 'n' / 'n'", PEG)
+		assert.ErrorIs(t, err, ErrLeftRecursion)
+	})
+
+	t.Run("should reject left recursion through nullable prefix", func(t *testing.T) {
+		_, err := Load("A <- S B\nB <- A 'x' / 'y'\nS <- ' '*", PEG)
+		assert.ErrorIs(t, err, ErrLeftRecursion)
+	})
+}
+
+//nolint:paralleltest // tests share parser state
+func TestGrammar_Parse(t *testing.T) {
+	t.Run("should build parse tree with rule names", func(t *testing.T) {
+		g, err := Load(listPEG, PEG)
+		require.NoError(t, err)
+
+		tree, err := g.Parse("List", "[1, 22,\n333]")
+		require.NoError(t, err)
+
+		assert.Equal(t, "List", tree.Rule)
+		items, ok := tree.Find("Items")
+		require.True(t, ok)
+
+		numbers := items.FindAll("Number")
+		require.Len(t, numbers, 3)
+		assert.Equal(t, "1", numbers[0].Text)
+		assert.Equal(t, "22", numbers[1].Text)
+		assert.Equal(t, "333", numbers[2].Text)
+	})
+
+	t.Run("should record spans", func(t *testing.T) {
+		g, err := Load(listPEG, PEG)
+		require.NoError(t, err)
+
+		tree, err := g.Parse("List", "[1,\n  42]")
+		require.NoError(t, err)
+
+		items, _ := tree.Find("Items")
+		last := items.FindAll("Number")[1]
+		assert.Equal(t, Position{Offset: 6, Line: 2, Col: 3}, last.Span.Start)
+		assert.Equal(t, Position{Offset: 8, Line: 2, Col: 5}, last.Span.End)
+	})
+
+	t.Run("should splice transparent rules", func(t *testing.T) {
+		g, err := Load(listPEG, PEG)
+		require.NoError(t, err)
+
+		tree, err := g.Parse("List", "[ 1]")
+		require.NoError(t, err)
+		for _, child := range tree.Children {
+			assert.NotEqual(t, "_", child.Rule)
+		}
+	})
+
+	t.Run("should parse with EBNF grammar", func(t *testing.T) {
+		g, err := Load(listEBNF, EBNF)
+		require.NoError(t, err)
+
+		tree, err := g.Parse("list", "[4, 5]")
+		require.NoError(t, err)
+		items, ok := tree.Find("items")
+		require.True(t, ok)
+		assert.Len(t, items.FindAll("number"), 2)
+	})
+
+	t.Run("should accept empty optional part", func(t *testing.T) {
+		g, err := Load(listEBNF, EBNF)
+		require.NoError(t, err)
+
+		tree, err := g.Parse("list", "[]")
+		require.NoError(t, err)
+		assert.Empty(t, tree.Children)
+	})
+
+	t.Run("should require whole input", func(t *testing.T) {
+		g, err := Load(listPEG, PEG)
+		require.NoError(t, err)
+
+		_, err = g.Parse("List", "[1] extra")
+		assert.Error(t, err)
+	})
+
+	t.Run("should report unknown start rule", func(t *testing.T) {
+		g, err := Load(listPEG, PEG)
+		require.NoError(t, err)
+
+		_, err = g.Parse("Missing", "[]")
+		assert.ErrorIs(t, err, ErrUndefinedRule)
+	})
+}
+
+//nolint:paralleltest // tests share parser state
+func TestGrammar_Operators(t *testing.T) {
+	cases := []struct {
+		name    string
+		grammar string
+		syntax  Syntax
+		input   string
+		ok      bool
+	}{
+		{"ordered choice", "S <- 'ab' / 'a'", PEG, "ab", true},
+		{"any character", "S <- . .", PEG, "xy", true},
+		{"negated class", `S <- [^"]+`, PEG, "abc", true},
+		{"negated class rejects", `S <- [^"]+`, PEG, `"`, false},
+		{"not predicate", "S <- !'x' .", PEG, "x", false},
+		{"and predicate", "S <- &'x' .", PEG, "x", true},
+		{"plus requires one", "S <- 'a'+", PEG, "", false},
+		{"escaped literal", `S <- '\n'`, PEG, "\n", true},
+		{"arrow definition", "S ← 'a'", PEG, "a", true},
+		{"ebnf alternation", `S = "x" | "y" ;`, EBNF, "y", true},
+		{"ebnf repetition", `S = { "ab" } ;`, EBNF, "ababab", true},
+		{"ebnf postfix", `S = "a"+ , "b"? ;`, EBNF, "aaa", true},
+		{"ebnf without commas", `S = "a" "b"`, EBNF, "ab", true},
+		{"ebnf range", `S = "a".."c" ;`, EBNF, "d", false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			g, err := Load(tc.grammar, tc.syntax)
+			require.NoError(t, err)
+
+			_, err = g.Parse(g.Rules()[0], tc.input)
+			assert.Equal(t, tc.ok, err == nil, "err: %v", err)
+		})
+	}
+}
+
+//nolint:paralleltest // tests share parser state
+func TestGrammar_Parser(t *testing.T) {
+	t.Run("should compose with combinator parsers", func(t *testing.T) {
+		g, err := Load("Word <- [a-z]+", PEG)
+		require.NoError(t, err)
+
+		word, err := g.Parser("Word")
+		require.NoError(t, err)
+
+		words := combinator.SepBy1(word, combinator.Char(' '))
+		result := combinator.Parse(words, "hello big world")
+		require.True(t, result.OK)
+		require.Len(t, result.Value, 3)
+		assert.Equal(t, "world", result.Value[2].Text)
+	})
+
+	t.Run("should support recursive rules", func(t *testing.T) {
+		g, err := Load("Nested <- '(' Nested* ')'", PEG)
+		require.NoError(t, err)
+
+		tree, err := g.Parse("Nested", "(()(()))")
+		require.NoError(t, err)
+		assert.Len(t, tree.Children, 2)
+		assert.Len(t, tree.Children[1].Children, 1)
+	})
+}
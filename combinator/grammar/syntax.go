@@ -0,0 +1,307 @@
+package grammar
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/dottermi/x/combinator"
+)
+
+// definition is a rule as written in the grammar text.
+type definition struct {
+	name      string
+	body      expr
+	line, col int
+}
+
+// parseGrammar parses grammar text into its rule definitions.
+func parseGrammar(src string, syntax Syntax) ([]definition, error) {
+	sp := newSyntaxParser(syntax)
+
+	res := combinator.Parse(combinator.Right(sp.spacing, combinator.Many(sp.definition())), src)
+	if res.State.IsEOF() {
+		if len(res.Value) == 0 {
+			return nil, fmt.Errorf("%w: grammar has no rules", ErrSyntax)
+		}
+		return res.Value, nil
+	}
+
+	// Re-run the failing definition to surface its error.
+	if r := sp.definition()(res.State); !r.OK {
+		return nil, syntaxError(r.Err)
+	}
+	return nil, syntaxError(fmt.Errorf("unexpected '%c' at line %d, col %d", res.State.Current(), res.State.Line, res.State.Col))
+}
+
+// syntaxParser holds the token parsers for one grammar notation.
+type syntaxParser struct {
+	syntax  Syntax
+	spacing combinator.Parser[struct{}]
+	expr    combinator.Rule[expr]
+}
+
+func newSyntaxParser(syntax Syntax) *syntaxParser {
+	sp := &syntaxParser{syntax: syntax}
+
+	var comment combinator.Parser[string]
+	if syntax == EBNF {
+		comment = combinator.Right(combinator.String("(*"), combinator.Map(
+			combinator.Many(combinator.Right(combinator.Not(combinator.String("*)")), combinator.Any())),
+			func(rs []rune) string { return string(rs) },
+		))
+		comment = combinator.Left(comment, combinator.String("*)"))
+	} else {
+		comment = combinator.Right(combinator.Char('#'), combinator.Map(
+			combinator.Many(combinator.NoneOf("\n")),
+			func(rs []rune) string { return string(rs) },
+		))
+	}
+	sp.spacing = combinator.SkipMany(combinator.Choice(combinator.Spaces1(), comment))
+
+	// Build the expression parser once; nested references resolve through sp.expr.
+	var body combinator.Parser[expr]
+	sp.expr = func() combinator.Parser[expr] { return body }
+	if syntax == EBNF {
+		body = sp.ebnfExpression()
+	} else {
+		body = sp.pegExpression()
+	}
+	return sp
+}
+
+// lex makes p consume trailing whitespace and comments.
+func lex[T any](sp *syntaxParser, p combinator.Parser[T]) combinator.Parser[T] {
+	return combinator.Left(p, sp.spacing)
+}
+
+func (sp *syntaxParser) symbol(s string) combinator.Parser[string] {
+	return lex(sp, combinator.String(s))
+}
+
+func (sp *syntaxParser) assign() combinator.Parser[string] {
+	if sp.syntax == EBNF {
+		return combinator.Choice(sp.symbol("::="), sp.symbol("="))
+	}
+	return combinator.Choice(sp.symbol("<-"), sp.symbol("←"))
+}
+
+func (sp *syntaxParser) definition() combinator.Parser[definition] {
+	name := lex(sp, combinator.Label(combinator.Ident(), "rule name"))
+	terminator := combinator.Opt(sp.symbol(";"))
+
+	return func(state combinator.State) combinator.Result[definition] {
+		current := state
+
+		n, err := combinator.Run(name, &current)
+		if err != nil {
+			return combinator.Failure[definition](err, current)
+		}
+		if _, err := combinator.Run(combinator.Label(sp.assign(), "rule definition"), &current); err != nil {
+			return combinator.Failure[definition](err, current)
+		}
+		body, err := combinator.Run(combinator.Ref(&sp.expr), &current)
+		if err != nil {
+			return combinator.Failure[definition](err, current)
+		}
+		if _, err := combinator.Run(terminator, &current); err != nil {
+			return combinator.Failure[definition](err, current)
+		}
+
+		return combinator.Success(definition{name: n, body: body, line: state.Line, col: state.Col}, current)
+	}
+}
+
+// reference parses a rule name that is not the start of the next definition.
+func (sp *syntaxParser) reference() combinator.Parser[expr] {
+	name := combinator.Left(lex(sp, combinator.Ident()), combinator.Not(sp.assign()))
+
+	return func(state combinator.State) combinator.Result[expr] {
+		r := name(state)
+		if !r.OK {
+			return combinator.Failure[expr](r.Err, r.State)
+		}
+		return combinator.Success[expr](refExpr{name: r.Value, line: state.Line, col: state.Col}, r.State)
+	}
+}
+
+func (sp *syntaxParser) pegExpression() combinator.Parser[expr] {
+	primary := combinator.Choice(
+		sp.reference(),
+		combinator.Between(sp.symbol("("), sp.symbol(")"), combinator.Ref(&sp.expr)),
+		lex(sp, sp.literal()),
+		lex(sp, sp.class()),
+		combinator.Map(sp.symbol("."), func(string) expr { return anyExpr{} }),
+	)
+
+	suffix := combinator.Map(
+		combinator.Seq2(primary, combinator.Opt(lex(sp, combinator.OneOf("*+?")))),
+		func(p combinator.Pair[expr, *rune]) expr {
+			if p.Second == nil {
+				return p.First
+			}
+			return repetition(p.First, *p.Second)
+		},
+	)
+
+	prefix := combinator.Map(
+		combinator.Seq2(combinator.Opt(lex(sp, combinator.OneOf("&!"))), suffix),
+		func(p combinator.Pair[*rune, expr]) expr {
+			if p.First == nil {
+				return p.Second
+			}
+			return predExpr{inner: p.Second, not: *p.First == '!'}
+		},
+	)
+
+	return alternatives(combinator.SepBy1(sequence(combinator.Many(prefix)), sp.symbol("/")))
+}
+
+func (sp *syntaxParser) ebnfExpression() combinator.Parser[expr] {
+	nested := combinator.Ref(&sp.expr)
+
+	factor := combinator.Choice(
+		sp.reference(),
+		combinator.Between(sp.symbol("("), sp.symbol(")"), nested),
+		combinator.Map(combinator.Between(sp.symbol("["), sp.symbol("]"), nested), func(e expr) expr {
+			return repeatExpr{inner: e, min: 0, max: 1}
+		}),
+		combinator.Map(combinator.Between(sp.symbol("{"), sp.symbol("}"), nested), func(e expr) expr {
+			return repeatExpr{inner: e, min: 0, max: -1}
+		}),
+		lex(sp, sp.literalOrRange()),
+	)
+
+	term := combinator.Map(
+		combinator.Seq2(factor, combinator.Opt(lex(sp, combinator.OneOf("*+?")))),
+		func(p combinator.Pair[expr, *rune]) expr {
+			if p.Second == nil {
+				return p.First
+			}
+			return repetition(p.First, *p.Second)
+		},
+	)
+
+	terms := combinator.Map(
+		combinator.Opt(combinator.SepBy1(term, combinator.Opt(sp.symbol(",")))),
+		func(items *[]expr) []expr {
+			if items == nil {
+				return nil
+			}
+			return *items
+		},
+	)
+
+	return alternatives(combinator.SepBy1(sequence(terms), sp.symbol("|")))
+}
+
+// literal parses a single- or double-quoted string.
+func (sp *syntaxParser) literal() combinator.Parser[expr] {
+	return combinator.Map(quoted(), func(s string) expr {
+		return litExpr(s)
+	})
+}
+
+// quoted parses the contents of a single- or double-quoted string.
+func quoted() combinator.Parser[string] {
+	quote := func(q rune) combinator.Parser[string] {
+		content := combinator.Many(combinator.Choice(escape(), combinator.NoneOf(string(q)+"\\")))
+		return combinator.Map(combinator.Between(combinator.Char(q), combinator.Char(q), content), func(rs []rune) string {
+			return string(rs)
+		})
+	}
+	return combinator.Choice(quote('\''), quote('"'))
+}
+
+// literalOrRange parses a literal optionally followed by .. and a second literal,
+// forming an inclusive character range.
+func (sp *syntaxParser) literalOrRange() combinator.Parser[expr] {
+	lit := quoted()
+	upper := combinator.Right(combinator.Right(sp.spacing, sp.symbol("..")), lit)
+
+	return func(state combinator.State) combinator.Result[expr] {
+		r := lit(state)
+		if !r.OK {
+			return combinator.Failure[expr](r.Err, r.State)
+		}
+
+		hi := upper(r.State)
+		if !hi.OK {
+			return combinator.Success[expr](litExpr(r.Value), r.State)
+		}
+
+		from, to := r.Value, hi.Value
+		if utf8.RuneCountInString(from) != 1 || utf8.RuneCountInString(to) != 1 {
+			return combinator.Failure[expr](fmt.Errorf("range bounds must be single characters at line %d, col %d", state.Line, state.Col), state)
+		}
+
+		lo, _ := utf8.DecodeRuneInString(from)
+		up, _ := utf8.DecodeRuneInString(to)
+		return combinator.Success[expr](classExpr{ranges: []runeRange{{lo: lo, hi: up}}}, hi.State)
+	}
+}
+
+// class parses a bracketed character class like [a-z_] or [^"].
+func (sp *syntaxParser) class() combinator.Parser[expr] {
+	char := combinator.Choice(escape(), combinator.NoneOf("]\\"))
+	item := combinator.Map(
+		combinator.Seq2(char, combinator.Opt(combinator.Right(combinator.Char('-'), char))),
+		func(p combinator.Pair[rune, *rune]) runeRange {
+			if p.Second == nil {
+				return runeRange{lo: p.First, hi: p.First}
+			}
+			return runeRange{lo: p.First, hi: *p.Second}
+		},
+	)
+
+	body := combinator.Seq2(combinator.Opt(combinator.Char('^')), combinator.Many(item))
+	return combinator.Map(combinator.Between(combinator.Char('['), combinator.Char(']'), body), func(p combinator.Pair[*rune, []runeRange]) expr {
+		return classExpr{ranges: p.Second, negated: p.First != nil}
+	})
+}
+
+// escape parses a backslash escape inside a literal or class.
+// Recognizes \n, \r and \t; any other escaped character stands for itself.
+func escape() combinator.Parser[rune] {
+	return combinator.Right(combinator.Char('\\'), combinator.Map(combinator.Any(), func(r rune) rune {
+		switch r {
+		case 'n':
+			return '\n'
+		case 'r':
+			return '\r'
+		case 't':
+			return '\t'
+		}
+		return r
+	}))
+}
+
+func repetition(inner expr, op rune) expr {
+	switch op {
+	case '*':
+		return repeatExpr{inner: inner, min: 0, max: -1}
+	case '+':
+		return repeatExpr{inner: inner, min: 1, max: -1}
+	default:
+		return repeatExpr{inner: inner, min: 0, max: 1}
+	}
+}
+
+// sequence collapses single-item sequences into the item itself.
+func sequence(items combinator.Parser[[]expr]) combinator.Parser[expr] {
+	return combinator.Map(items, func(es []expr) expr {
+		if len(es) == 1 {
+			return es[0]
+		}
+		return seqExpr(es)
+	})
+}
+
+// alternatives collapses single-alternative choices into the alternative itself.
+func alternatives(alts combinator.Parser[[]expr]) combinator.Parser[expr] {
+	return combinator.Map(alts, func(es []expr) expr {
+		if len(es) == 1 {
+			return es[0]
+		}
+		return choiceExpr(es)
+	})
+}
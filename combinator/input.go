@@ -0,0 +1,107 @@
+package combinator
+
+import "fmt"
+
+// Item matches a single input element equal to want and returns it.
+// It is the counterpart of [Char] for inputs that are not text, such as
+// tokens produced by a separate lexer.
+//
+// Example:
+//
+//	semi := Item(Token{Kind: "punct", Text: ";"})
+//	result := ParseInput(semi, tokens)
+func Item[I comparable](want I) GParser[I, I] {
+	text := describe(want)
+
+	return func(state GState[I]) GResult[I, I] {
+		if state.IsEOF() {
+			return Failure[I](newError(errExpectedAtEOF, text, state), state)
+		}
+
+		if state.Current() != want {
+			return Failure[I](newError(errMismatch, text, state), state)
+		}
+
+		return Success(want, state.Advance())
+	}
+}
+
+// ItemIf matches a single input element that satisfies the predicate and
+// returns it. It is the counterpart of [Satisfy] for inputs that are not text.
+//
+// Example:
+//
+//	ident := ItemIf(func(t Token) bool { return t.Kind == "ident" })
+func ItemIf[I any](pred func(I) bool) GParser[I, I] {
+	return func(state GState[I]) GResult[I, I] {
+		if state.IsEOF() {
+			return Failure[I](newError(errUnexpectedEOF, "", state), state)
+		}
+
+		e := state.Current()
+		if !pred(e) {
+			return Failure[I](newError(errUnexpected, "", state), state)
+		}
+
+		return Success(e, state.Advance())
+	}
+}
+
+// AnyItem matches any single input element and returns it.
+// Fails only at end of input.
+func AnyItem[I any]() GParser[I, I] {
+	return func(state GState[I]) GResult[I, I] {
+		if state.IsEOF() {
+			return Failure[I](newError(errUnexpectedEOF, "", state), state)
+		}
+
+		return Success(state.Current(), state.Advance())
+	}
+}
+
+// EndOfInput matches the end of an input of any element type.
+// [EOF] is EndOfInput for text.
+//
+// Example:
+//
+//	program := Left(Many(statement), EndOfInput[Token]())
+func EndOfInput[I any]() GParser[struct{}, I] {
+	return func(state GState[I]) GResult[struct{}, I] {
+		if !state.IsEOF() {
+			return Failure[struct{}](newError(errExpectedEOF, "", state), state)
+		}
+
+		return Success(struct{}{}, state)
+	}
+}
+
+// RecognizeItems runs a parser and returns the input elements it consumed,
+// discarding its value. It is the counterpart of [Recognize] for inputs that
+// are not text. The returned slice shares memory with the input.
+//
+// Example:
+//
+//	line := RecognizeItems(Many(ItemIf(func(b byte) bool { return b != '\n' })))
+//	result := ParseInput(line, []byte("GET / HTTP/1.1\r\n"))
+//	// string(result.Value) == "GET / HTTP/1.1\r"
+func RecognizeItems[T, I any](p GParser[T, I]) GParser[[]I, I] {
+	return func(state GState[I]) GResult[[]I, I] {
+		r := p(state)
+		if !r.OK {
+			return Failure[[]I](r.Err, r.State)
+		}
+		return Success(state.Input[state.Pos:r.State.Pos:r.State.Pos], r.State)
+	}
+}
+
+// describe formats an input element for error messages, showing runes and
+// bytes as characters.
+func describe[I any](e I) string {
+	switch e := any(e).(type) {
+	case rune:
+		return string(e)
+	case byte:
+		return string(rune(e))
+	}
+	return fmt.Sprint(e)
+}
@@ -0,0 +1,113 @@
+package combinator
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type tok struct {
+	kind string
+	text string
+}
+
+func (t tok) String() string {
+	return t.text
+}
+
+func kind(k string) GParser[tok, tok] {
+	return ItemIf(func(t tok) bool { return t.kind == k })
+}
+
+// sumTokens parses pre-lexed sums like 1 + 2 + 3 with the text combinators.
+func sumTokens() GParser[int, tok] {
+	num := Map(kind("num"), func(t tok) int {
+		n, _ := strconv.Atoi(t.text)
+		return n
+	})
+	plus := Map(Item(tok{"op", "+"}), func(tok) func(int, int) int {
+		return func(a, b int) int { return a + b }
+	})
+	return Left(ChainL1(num, plus), EndOfInput[tok]())
+}
+
+//nolint:paralleltest // tests share parser state
+func TestParseInput(t *testing.T) {
+	t.Run("should parse tokens with the generic combinators", func(t *testing.T) {
+		input := []tok{{"num", "1"}, {"op", "+"}, {"num", "2"}, {"op", "+"}, {"num", "39"}}
+		result := ParseInput(sumTokens(), input)
+		require.True(t, result.OK, result.Err)
+		assert.Equal(t, 42, result.Value)
+		assert.Equal(t, 6, result.State.Col)
+	})
+
+	t.Run("should report the offending token", func(t *testing.T) {
+		input := []tok{{"num", "1"}, {"op", "+"}, {"op", "+"}}
+		result := ParseInput(sumTokens(), input)
+		require.False(t, result.OK)
+		assert.EqualError(t, result.Err, "expected EOF, got '+' at line 1, col 2")
+	})
+
+	t.Run("should report mismatched items", func(t *testing.T) {
+		result := ParseInput(Item(tok{"op", "+"}), []tok{{"num", "7"}})
+		require.False(t, result.OK)
+		assert.EqualError(t, result.Err, "expected '+', got '7' at line 1, col 1")
+
+		result = ParseInput(Item(tok{"op", "+"}), nil)
+		assert.EqualError(t, result.Err, "unexpected EOF, expected '+' at line 1, col 1")
+	})
+
+	t.Run("should track lines in bytes", func(t *testing.T) {
+		notNewline := ItemIf(func(b byte) bool { return b != '\n' })
+		line := RecognizeItems(Many(notNewline))
+		lines := SepBy(line, Item(byte('\n')))
+
+		result := ParseInput(Left(lines, EndOfInput[byte]()), []byte("GET /\nHost: x\n\nbody"))
+		require.True(t, result.OK, result.Err)
+		require.Len(t, result.Value, 4)
+		assert.Equal(t, "Host: x", string(result.Value[1]))
+		assert.Empty(t, result.Value[2])
+		assert.Equal(t, 4, result.State.Line)
+		assert.Equal(t, 5, result.State.Col)
+
+		result = ParseInput(Left(lines, Item(byte('!'))), []byte("ab\ncd"))
+		require.False(t, result.OK)
+		assert.EqualError(t, result.Err, "unexpected EOF, expected '!' at line 2, col 3")
+	})
+
+	t.Run("should parse lines of a file", func(t *testing.T) {
+		heading := ItemIf(func(s string) bool { return len(s) > 0 && s[0] == '#' })
+		body := Many(ItemIf(func(s string) bool { return len(s) == 0 || s[0] != '#' }))
+		section := Seq2(heading, body)
+
+		result := ParseInput(Many(section), []string{"# a", "x", "y", "# b", ""})
+		require.True(t, result.OK, result.Err)
+		require.Len(t, result.Value, 2)
+		assert.Equal(t, []string{"x", "y"}, result.Value[0].Second)
+		assert.Equal(t, 5, result.State.Position().Offset)
+	})
+
+	t.Run("should support rules, limits and events", func(t *testing.T) {
+		var nested GRule[int, rune]
+		nested = func() Parser[int] {
+			return Choice(
+				Map(Between(Char('('), Char(')'), Ref(&nested)), func(n int) int { return n + 1 }),
+				Map(Not(Char('(')), func(struct{}) int { return 0 }),
+			)
+		}
+		assert.Equal(t, 3, Parse(Ref(&nested), "((()))").Value)
+
+		kinds := map[EventKind]string{EventStart: "start", EventEnd: "end", EventValue: "value"}
+		var events []string
+		list := Emit("list", Many(EmitValue("item", AnyItem[int]())))
+		result := ParseInput(list, []int{4, 2}, WithMaxSteps(10), WithEvents(func(e Event) bool {
+			events = append(events, fmt.Sprintf("%s %s %v@%d", kinds[e.Kind], e.Name, e.Value, e.Pos))
+			return true
+		}))
+		require.True(t, result.OK, result.Err)
+		assert.Equal(t, []string{"start list <nil>@0", "value item 4@0", "value item 2@1", "end list <nil>@2"}, events)
+	})
+}
@@ -0,0 +1,128 @@
+package combinator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Errors reported when a parse is aborted by one of its limits.
+// Use [errors.Is] to tell them apart from ordinary parse failures.
+var (
+	ErrCanceled   = errors.New("parse canceled")
+	ErrStepLimit  = errors.New("parse step limit exceeded")
+	ErrDepthLimit = errors.New("parse recursion depth limit exceeded")
+)
+
+// cancelCheckInterval is how many rule invocations pass between context checks.
+const cancelCheckInterval = 64
+
+// Option configures limits and event delivery for a parse started with [Parse].
+type Option func(*session)
+
+// WithContext aborts the parse with [ErrCanceled] once ctx is done.
+// The context error is wrapped as well, so errors.Is(err, context.DeadlineExceeded) works.
+// The context is checked by [Ref] and [Lazy] and on each iteration of repetitions
+// such as [Many], [SepBy], [SkipMany], [ChainL1] and [TakeUntil].
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+//	defer cancel()
+//	result := Parse(Ref(&expr), input, WithContext(ctx))
+func WithContext(ctx context.Context) Option {
+	return func(s *session) {
+		s.ctx = ctx
+	}
+}
+
+// WithMaxSteps aborts the parse with [ErrStepLimit] after n invocations of
+// [Ref] or [Lazy] parsers. Bounds the backtracking work of recursive grammars.
+func WithMaxSteps(n int) Option {
+	return func(s *session) {
+		s.maxSteps = n
+	}
+}
+
+// WithMaxDepth aborts the parse with [ErrDepthLimit] when [Ref] or [Lazy] parsers
+// nest more than n levels deep. Protects against stack exhaustion on deeply
+// nested input like "((((((...".
+func WithMaxDepth(n int) Option {
+	return func(s *session) {
+		s.maxDepth = n
+	}
+}
+
+// enter accounts for a rule invocation and reports an error if a limit tripped.
+// Every successful enter must be paired with a call to leave.
+func (s GState[I]) enter() error {
+	ss := s.session
+	if ss == nil {
+		return nil
+	}
+	if ss.err != nil {
+		return ss.err
+	}
+
+	ss.steps++
+	if ss.maxSteps > 0 && ss.steps > ss.maxSteps {
+		return ss.abort(fmt.Errorf("%w: %d steps %s", ErrStepLimit, ss.maxSteps, s.at()))
+	}
+	if ss.maxDepth > 0 && ss.depth >= ss.maxDepth {
+		return ss.abort(fmt.Errorf("%w: %d levels %s", ErrDepthLimit, ss.maxDepth, s.at()))
+	}
+	if ss.ctx != nil && ss.steps%cancelCheckInterval == 1 {
+		select {
+		case <-ss.ctx.Done():
+			return ss.abort(fmt.Errorf("%w %s: %w", ErrCanceled, s.at(), ss.ctx.Err()))
+		default:
+		}
+	}
+
+	ss.depth++
+	return nil
+}
+
+// checkpoint is called on each iteration of the repetition combinators, so a
+// canceled parse stops in loops that never invoke a rule. Reports the reason
+// the parse aborted, if it did.
+func (s GState[I]) checkpoint() error {
+	ss := s.session
+	if ss == nil {
+		return nil
+	}
+	if ss.err != nil {
+		return ss.err
+	}
+
+	ss.polls++
+	if ss.ctx != nil && ss.polls%cancelCheckInterval == 1 {
+		select {
+		case <-ss.ctx.Done():
+			return ss.abort(fmt.Errorf("%w %s: %w", ErrCanceled, s.at(), ss.ctx.Err()))
+		default:
+		}
+	}
+	return nil
+}
+
+// leave undoes the nesting recorded by a successful enter.
+func (s GState[I]) leave() {
+	if s.session != nil {
+		s.session.depth--
+	}
+}
+
+// aborted reports whether a limit has tripped during this parse.
+func (s GState[I]) aborted() bool {
+	return s.session != nil && s.session.err != nil
+}
+
+// abort records err as the reason the parse stopped and returns the recorded
+// reason. The first abort wins, so the root cause is what gets reported.
+func (ss *session) abort(err error) error {
+	if ss.err == nil {
+		ss.err = err
+	}
+	return ss.err
+}
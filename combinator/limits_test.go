@@ -0,0 +1,115 @@
+package combinator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// backtracking returns a grammar that tries two alternatives per nesting level,
+// taking exponential time on unbalanced input like "((((((".
+func backtracking() Parser[string] {
+	var nested Rule[string]
+	nested = func() Parser[string] {
+		return Choice(
+			Right(Char('('), Left(Ref(&nested), Char(')'))),
+			Right(Char('('), Left(Ref(&nested), Char(']'))),
+			String(""),
+		)
+	}
+	return Ref(&nested)
+}
+
+func nestedParens() Parser[int64] {
+	var expr Rule[int64]
+	expr = func() Parser[int64] {
+		return Choice(Integer(), Parens(Ref(&expr)))
+	}
+	return Ref(&expr)
+}
+
+//nolint:paralleltest // tests share parser state
+func TestWithMaxSteps(t *testing.T) {
+	t.Run("should abort runaway backtracking", func(t *testing.T) {
+		result := Parse(backtracking(), strings.Repeat("(", 40), WithMaxSteps(10_000))
+		require.False(t, result.OK)
+		assert.ErrorIs(t, result.Err, ErrStepLimit)
+	})
+
+	t.Run("should not affect parses within the limit", func(t *testing.T) {
+		result := Parse(nestedParens(), "((42))", WithMaxSteps(100))
+		require.True(t, result.OK)
+		assert.Equal(t, int64(42), result.Value)
+	})
+
+	t.Run("should fail even if a later alternative succeeds", func(t *testing.T) {
+		parser := Choice(Skip(nestedParens()), Skip(String("((((")))
+		result := Parse(parser, "((((1))))", WithMaxSteps(2))
+		assert.ErrorIs(t, result.Err, ErrStepLimit)
+	})
+}
+
+//nolint:paralleltest // tests share parser state
+func TestWithMaxDepth(t *testing.T) {
+	t.Run("should abort deep nesting", func(t *testing.T) {
+		input := strings.Repeat("(", 10_000) + "1" + strings.Repeat(")", 10_000)
+		result := Parse(nestedParens(), input, WithMaxDepth(100))
+		require.False(t, result.OK)
+		assert.ErrorIs(t, result.Err, ErrDepthLimit)
+	})
+
+	t.Run("should release depth after each rule returns", func(t *testing.T) {
+		var item Rule[rune]
+		item = func() Parser[rune] { return Char('a') }
+
+		result := Parse(Many(Ref(&item)), "aaaaaaaa", WithMaxDepth(1))
+		require.True(t, result.OK)
+		assert.Len(t, result.Value, 8)
+	})
+
+	t.Run("should apply to lazy parsers", func(t *testing.T) {
+		var p Parser[struct{}]
+		p = Lazy(func() Parser[struct{}] {
+			return Choice(Right(Char('['), Left(p, Char(']'))), Skip(String("")))
+		})
+		result := Parse(p, "[[[[[]]]]]", WithMaxDepth(3))
+		assert.ErrorIs(t, result.Err, ErrDepthLimit)
+	})
+}
+
+//nolint:paralleltest // tests share parser state
+func TestWithContext(t *testing.T) {
+	t.Run("should abort when context is canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		result := Parse(backtracking(), strings.Repeat("(", 40), WithContext(ctx))
+		require.False(t, result.OK)
+		assert.ErrorIs(t, result.Err, ErrCanceled)
+		assert.ErrorIs(t, result.Err, context.Canceled)
+	})
+
+	t.Run("should succeed with live context", func(t *testing.T) {
+		result := Parse(nestedParens(), "(7)", WithContext(context.Background()))
+		assert.True(t, result.OK)
+	})
+
+	t.Run("should abort repetitions without rules", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		for _, p := range []Parser[struct{}]{
+			Skip(Many(Char('a'))),
+			Skip(SepBy(Char('a'), Char(','))),
+			SkipMany(Char('a')),
+			Skip(TakeUntil(Char('!'))),
+		} {
+			result := Parse(p, strings.Repeat("a,", 10), WithContext(ctx))
+			require.False(t, result.OK)
+			assert.ErrorIs(t, result.Err, ErrCanceled)
+		}
+	})
+}
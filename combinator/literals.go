@@ -2,7 +2,7 @@ package combinator
 
 import (
 	"strconv"
-	"strings"
+	"unicode"
 )
 
 // Ident matches a programming language identifier.
@@ -14,17 +14,25 @@ import (
 //	result := Parse(Ident(), "myVar123")
 //	// result.Value == "myVar123"
 func Ident() Parser[string] {
-	first := Choice(Letter(), Char('_'))
-	rest := Many(Choice(AlphaNum(), Char('_')))
-
-	return Map(Seq2(first, rest), func(p Pair[rune, []rune]) string {
-		var sb strings.Builder
-		sb.WriteRune(p.First)
-		for _, r := range p.Second {
-			sb.WriteRune(r)
+	first := Label(Satisfy(isIdentStart), "identifier")
+
+	return func(state State) Result[string] {
+		if state.IsEOF() || !isIdentStart(state.Current()) {
+			r := first(state)
+			return Failure[string](r.Err, r.State)
 		}
-		return sb.String()
-	})
+
+		next := state.advanceTo(state.scan(state.Pos+1, isIdentPart))
+		return Success(state.text(next), next)
+	}
+}
+
+func isIdentStart(r rune) bool {
+	return unicode.IsLetter(r) || r == '_'
+}
+
+func isIdentPart(r rune) bool {
+	return isAlphaNum(r) || r == '_'
 }
 
 // Keyword matches a specific keyword that is not followed by alphanumeric characters.
@@ -35,7 +43,15 @@ func Ident() Parser[string] {
 //	result := Parse(Keyword("if"), "if (x)")  // succeeds
 //	result = Parse(Keyword("if"), "iffy")    // fails
 func Keyword(kw string) Parser[string] {
-	return Left(String(kw), Not(AlphaNum()))
+	word := String(kw)
+
+	return func(state State) Result[string] {
+		r := word(state)
+		if r.OK && !r.State.IsEOF() && isAlphaNum(r.State.Current()) {
+			return Failure[string](newError(errUnexpectedMatch, "", r.State), r.State)
+		}
+		return r
+	}
 }
 
 // Integer matches an optionally negative integer and returns it as int64.
@@ -46,20 +62,24 @@ func Keyword(kw string) Parser[string] {
 //	result := Parse(Integer(), "-42")
 //	// result.Value == int64(-42)
 func Integer() Parser[int64] {
-	sign := Opt(Char('-'))
-	digits := Many1(Digit())
+	digits := Label(Satisfy(unicode.IsDigit), "digit")
 
-	return Map(Seq2(sign, digits), func(p Pair[*rune, []rune]) int64 {
-		var sb strings.Builder
-		if p.First != nil {
-			sb.WriteRune(*p.First)
+	return func(state State) Result[int64] {
+		current := state
+		if current.Current() == '-' {
+			current = current.Advance()
 		}
-		for _, r := range p.Second {
-			sb.WriteRune(r)
+
+		end := current.scan(current.Pos, unicode.IsDigit)
+		if end == current.Pos {
+			r := digits(current)
+			return Failure[int64](r.Err, r.State)
 		}
-		n, _ := strconv.ParseInt(sb.String(), 10, 64)
-		return n
-	})
+
+		next := current.advanceTo(end)
+		n, _ := strconv.ParseInt(state.text(next), 10, 64)
+		return Success(n, next)
+	}
 }
 
 // Float matches a decimal number and returns it as float64.
@@ -70,27 +90,29 @@ func Integer() Parser[int64] {
 //	result := Parse(Float(), "-3.14")
 //	// result.Value == float64(-3.14)
 func Float() Parser[float64] {
-	sign := Opt(Char('-'))
-	intPart := Many1(Digit())
-	decPart := Opt(Seq2(Char('.'), Many1(Digit())))
-
-	return Map(Seq3(sign, intPart, decPart), func(t Triple[*rune, []rune, *Pair[rune, []rune]]) float64 {
-		var sb strings.Builder
-		if t.First != nil {
-			sb.WriteRune(*t.First)
+	digits := Label(Satisfy(unicode.IsDigit), "digit")
+
+	return func(state State) Result[float64] {
+		current := state
+		if current.Current() == '-' {
+			current = current.Advance()
 		}
-		for _, r := range t.Second {
-			sb.WriteRune(r)
+
+		end := current.scan(current.Pos, unicode.IsDigit)
+		if end == current.Pos {
+			r := digits(current)
+			return Failure[float64](r.Err, r.State)
 		}
-		if t.Third != nil {
-			sb.WriteRune('.')
-			for _, r := range t.Third.Second {
-				sb.WriteRune(r)
-			}
+
+		// The decimal part is only consumed when the dot is followed by a digit.
+		if end+1 < len(current.Input) && current.Input[end] == '.' && unicode.IsDigit(current.Input[end+1]) {
+			end = current.scan(end+1, unicode.IsDigit)
 		}
-		f, _ := strconv.ParseFloat(sb.String(), 64)
-		return f
-	})
+
+		next := current.advanceTo(end)
+		f, _ := strconv.ParseFloat(state.text(next), 64)
+		return Success(f, next)
+	}
 }
 
 // StringLit matches a double-quoted string with basic escape support.
@@ -104,18 +126,29 @@ func Float() Parser[float64] {
 func StringLit() Parser[string] {
 	quote := Char('"')
 	escaped := Right(Char('\\'), Any())
-	regular := Satisfy(func(r rune) bool {
-		return r != '"' && r != '\\'
-	})
+	regular := Satisfy(isPlainStringRune)
 	content := Many(Choice(escaped, regular))
 
-	return Map(Seq3(quote, content, quote), func(t Triple[rune, []rune, rune]) string {
-		var sb strings.Builder
-		for _, r := range t.Second {
-			sb.WriteRune(r)
-		}
-		return sb.String()
+	escapedLit := Map(Seq3(quote, content, quote), func(t Triple[rune, []rune, rune]) string {
+		return string(t.Second)
 	})
+
+	return func(state State) Result[string] {
+		// Fast path: strings without escapes are sliced straight from the input.
+		if state.Current() == '"' {
+			end := state.scan(state.Pos+1, isPlainStringRune)
+			if end < len(state.Input) && state.Input[end] == '"' {
+				body := state.Advance()
+				closing := body.advanceTo(end)
+				return Success(body.text(closing), closing.Advance())
+			}
+		}
+		return escapedLit(state)
+	}
+}
+
+func isPlainStringRune(r rune) bool {
+	return r != '"' && r != '\\'
 }
 
 // CharLit matches a single-quoted character literal with escape support.
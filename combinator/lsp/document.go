@@ -0,0 +1,84 @@
+package lsp
+
+import (
+	"strings"
+	"unicode/utf16"
+)
+
+// document is an open text document and the results of its last analysis.
+type document struct {
+	text       string
+	lineStarts []int // lineStarts holds the byte offset where each line begins.
+
+	diagnostics []Diagnostic
+	symbols     []DocumentSymbol
+}
+
+func newDocument(text string) *document {
+	starts := []int{0}
+	for i := range len(text) {
+		if text[i] == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return &document{text: text, lineStarts: starts}
+}
+
+// line returns the text of the zero-based line without its line break.
+func (d *document) line(n int) string {
+	if n < 0 || n >= len(d.lineStarts) {
+		return ""
+	}
+	end := len(d.text)
+	if n+1 < len(d.lineStarts) {
+		end = d.lineStarts[n+1]
+	}
+	return strings.TrimRight(d.text[d.lineStarts[n]:end], "\r\n")
+}
+
+// position converts a 1-indexed line and rune column, as reported by the
+// combinator package, into a protocol position.
+func (d *document) position(line, col int) Position {
+	character := 0
+	n := 1
+	for _, r := range d.line(line - 1) {
+		if n >= col {
+			break
+		}
+		character += utf16.RuneLen(r)
+		n++
+	}
+	return Position{Line: line - 1, Character: character}
+}
+
+// offset converts a protocol position into a byte offset in the text,
+// clamping positions past the end of a line or of the document.
+func (d *document) offset(p Position) int {
+	if p.Line < 0 {
+		return 0
+	}
+	if p.Line >= len(d.lineStarts) {
+		return len(d.text)
+	}
+
+	start := d.lineStarts[p.Line]
+	line := d.line(p.Line)
+	units := 0
+	for i, r := range line {
+		if units >= p.Character {
+			return start + i
+		}
+		units += utf16.RuneLen(r)
+	}
+	return start + len(line)
+}
+
+// end returns the position just past the last character of the text.
+func (d *document) end() Position {
+	return d.lineEnd(len(d.lineStarts) - 1)
+}
+
+// lineEnd returns the position of the end of the zero-based line.
+func (d *document) lineEnd(line int) Position {
+	return Position{Line: line, Character: len(utf16.Encode([]rune(d.line(line))))}
+}
@@ -0,0 +1,361 @@
+// Package lsp hosts a Language Server Protocol server for grammars built with
+// the combinator package.
+//
+// A [Server] speaks JSON-RPC over any reader and writer pair, usually standard
+// input and output. It keeps open documents in sync, publishes the parse error
+// of each document as a diagnostic, lists document symbols from the spans of
+// [combinator.Emit] parsers, and offers completions from [combinator.Expected].
+//
+// Symbols come only from Emit spans registered with [WithSymbol]. A
+// [combinator.Label] names a parser in errors and completions but produces no
+// span; wrap the labeled parser in Emit to list it as a symbol.
+//
+//	fn := combinator.Emit("function", combinator.Seq3(
+//		combinator.Keyword("fn"),
+//		combinator.EmitValue("name", combinator.Token()),
+//		body,
+//	))
+//	server := lsp.New(program, lsp.WithName("mydsl"), lsp.WithSymbol("function", lsp.SymbolFunction))
+//	if err := server.ServeStdio(); err != nil {
+//		log.Fatal(err)
+//	}
+//
+// Documents are synchronized in full on every change.
+package lsp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dottermi/x/combinator"
+)
+
+// errExit stops the serve loop after an exit notification.
+var errExit = errors.New("exit")
+
+// ErrExitWithoutShutdown is returned by [Server.Serve] when the client sends
+// exit without a prior shutdown request. The protocol asks the server process
+// to exit with code 1 in that case.
+var ErrExitWithoutShutdown = errors.New("lsp: exit without shutdown")
+
+// Option configures a [Server].
+type Option func(*Server)
+
+// WithName sets the server name reported to clients and used as the source of diagnostics.
+func WithName(name string) Option {
+	return func(s *Server) {
+		s.name = name
+	}
+}
+
+// WithSymbol reports spans of [combinator.Emit] parsers with the given event name
+// as document symbols of kind. The symbol is named after the first
+// [combinator.EmitValue] value inside the span, or the first line of its text.
+func WithSymbol(event string, kind SymbolKind) Option {
+	return func(s *Server) {
+		s.symbols[event] = kind
+	}
+}
+
+// Server is a language server for one grammar.
+// Create it with [New] and run it with [Server.Serve] or [Server.ServeStdio].
+type Server struct {
+	name     string
+	symbols  map[string]SymbolKind
+	parse    func(text string, opts ...combinator.Option) error
+	expected func(text string) []combinator.Expectation
+
+	conn        *conn
+	docs        map[string]*document
+	initialized bool
+	shutdown    bool // shutdown is set once the client requested shutdown.
+}
+
+// New returns a server for documents that must match p in full.
+func New[T any](p combinator.Parser[T], opts ...Option) *Server {
+	whole := combinator.Left(p, combinator.EOF())
+
+	s := &Server{
+		name:    "combinator",
+		symbols: make(map[string]SymbolKind),
+		parse: func(text string, opts ...combinator.Option) error {
+			return combinator.Parse(whole, text, opts...).Err
+		},
+		expected: func(text string) []combinator.Expectation {
+			return combinator.Expected(p, text)
+		},
+		docs: make(map[string]*document),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// ServeStdio serves the protocol over standard input and output.
+func (s *Server) ServeStdio() error {
+	return s.Serve(os.Stdin, os.Stdout)
+}
+
+// Serve reads requests from r and writes responses and notifications to w until
+// the client sends the exit notification or r is exhausted.
+// Returns [ErrExitWithoutShutdown] if exit was not preceded by shutdown.
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	s.conn = newConn(r, w)
+	s.shutdown = false
+
+	for {
+		msg, err := s.conn.read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		var rpcErr *responseError
+		if errors.As(err, &rpcErr) {
+			if err := s.conn.write(&message{ID: json.RawMessage("null"), Error: rpcErr}); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		err = s.handle(msg)
+		if errors.Is(err, errExit) {
+			if !s.shutdown {
+				return ErrExitWithoutShutdown
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// handle dispatches a message. Returns an error only when the connection fails
+// or the client asked the server to exit.
+func (s *Server) handle(msg *message) error {
+	isRequest := len(msg.ID) > 0
+
+	if msg.Method == "exit" {
+		return errExit
+	}
+	if !s.initialized && msg.Method != "initialize" {
+		if isRequest {
+			return s.fail(msg, codeNotInitialized, "server not initialized")
+		}
+		return nil
+	}
+
+	var result any
+	var err error
+
+	switch msg.Method {
+	case "initialize":
+		s.initialized = true
+		result = map[string]any{
+			"capabilities": map[string]any{
+				"textDocumentSync":       1, // full document sync
+				"documentSymbolProvider": true,
+				"completionProvider":     map[string]any{},
+			},
+			"serverInfo": map[string]string{"name": s.name},
+		}
+	case "shutdown":
+		s.shutdown = true
+		result = nil
+	case "textDocument/didOpen":
+		var params didOpenParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			return s.update(params.TextDocument.URI, params.TextDocument.Text)
+		}
+	case "textDocument/didChange":
+		var params didChangeParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil && len(params.ContentChanges) > 0 {
+			last := params.ContentChanges[len(params.ContentChanges)-1]
+			return s.update(params.TextDocument.URI, last.Text)
+		}
+	case "textDocument/didClose":
+		var params didCloseParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			delete(s.docs, params.TextDocument.URI)
+			return s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{
+				URI:         params.TextDocument.URI,
+				Diagnostics: []Diagnostic{},
+			})
+		}
+	case "textDocument/documentSymbol":
+		var params documentSymbolParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			result = s.documentSymbols(params.TextDocument.URI)
+		}
+	case "textDocument/completion":
+		var params completionParams
+		if err = json.Unmarshal(msg.Params, &params); err == nil {
+			result = s.completion(params.TextDocument.URI, params.Position)
+		}
+	default:
+		if isRequest {
+			return s.fail(msg, codeMethodNotFound, "method not found: "+msg.Method)
+		}
+		return nil
+	}
+
+	if !isRequest {
+		return nil
+	}
+	if err != nil {
+		return s.fail(msg, codeInvalidParams, err.Error())
+	}
+	return s.reply(msg, result)
+}
+
+func (s *Server) reply(req *message, result any) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	return s.conn.write(&message{ID: req.ID, Result: data})
+}
+
+func (s *Server) fail(req *message, code int, text string) error {
+	return s.conn.write(&message{ID: req.ID, Error: &responseError{Code: code, Message: text}})
+}
+
+func (s *Server) notify(method string, params any) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return s.conn.write(&message{Method: method, Params: data})
+}
+
+// update replaces the text of a document, analyzes it and publishes its diagnostics.
+func (s *Server) update(uri, text string) error {
+	doc := newDocument(text)
+	if old, ok := s.docs[uri]; ok {
+		// Symbols are only produced by successful parses; keep the last ones
+		// so the outline survives while the user is typing.
+		doc.symbols = old.symbols
+	}
+	s.analyze(doc)
+	s.docs[uri] = doc
+
+	return s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{
+		URI:         uri,
+		Diagnostics: doc.diagnostics,
+	})
+}
+
+// analyze parses the document, recording its diagnostics and symbols.
+func (s *Server) analyze(doc *document) {
+	var events []combinator.Event
+	err := s.parse(doc.text, combinator.WithEvents(func(e combinator.Event) bool {
+		events = append(events, e)
+		return true
+	}))
+
+	doc.diagnostics = []Diagnostic{}
+	if err != nil {
+		start := doc.end()
+		if line, col, ok := combinator.ErrorPosition(err); ok {
+			start = doc.position(line, col)
+		}
+		doc.diagnostics = append(doc.diagnostics, Diagnostic{
+			Range:    Range{Start: start, End: doc.lineEnd(start.Line)},
+			Severity: SeverityError,
+			Source:   s.name,
+			Message:  err.Error(),
+		})
+		return
+	}
+
+	doc.symbols = s.buildSymbols(doc, events)
+}
+
+// buildSymbols nests the spans of registered events into document symbols.
+func (s *Server) buildSymbols(doc *document, events []combinator.Event) []DocumentSymbol {
+	type frame struct {
+		symbol DocumentSymbol
+		start  combinator.Event
+	}
+
+	var roots []DocumentSymbol
+	var stack []*frame
+
+	for _, e := range events {
+		switch e.Kind {
+		case combinator.EventStart:
+			if kind, ok := s.symbols[e.Name]; ok {
+				stack = append(stack, &frame{symbol: DocumentSymbol{Detail: e.Name, Kind: kind}, start: e})
+			}
+		case combinator.EventValue:
+			if len(stack) > 0 && stack[len(stack)-1].symbol.Name == "" {
+				stack[len(stack)-1].symbol.Name = fmt.Sprint(e.Value)
+			}
+		case combinator.EventEnd:
+			if _, ok := s.symbols[e.Name]; !ok || len(stack) == 0 {
+				continue
+			}
+			f := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			sym := f.symbol
+			sym.Range = Range{Start: doc.position(f.start.Line, f.start.Col), End: doc.position(e.Line, e.Col)}
+			sym.SelectionRange = sym.Range
+			if sym.Name == "" {
+				text := doc.text[doc.offset(sym.Range.Start):doc.offset(sym.Range.End)]
+				sym.Name, _, _ = strings.Cut(strings.TrimSpace(text), "\n")
+			}
+
+			if len(stack) > 0 {
+				parent := &stack[len(stack)-1].symbol
+				parent.Children = append(parent.Children, sym)
+			} else {
+				roots = append(roots, sym)
+			}
+		}
+	}
+	return roots
+}
+
+func (s *Server) documentSymbols(uri string) []DocumentSymbol {
+	doc, ok := s.docs[uri]
+	if !ok || doc.symbols == nil {
+		return []DocumentSymbol{}
+	}
+	return doc.symbols
+}
+
+// completion lists the literals the grammar accepts at the cursor, each
+// replacing the partial word already typed.
+func (s *Server) completion(uri string, pos Position) []CompletionItem {
+	items := []CompletionItem{}
+	doc, ok := s.docs[uri]
+	if !ok {
+		return items
+	}
+
+	seen := make(map[string]bool)
+	for _, e := range s.expected(doc.text[:doc.offset(pos)]) {
+		if e.Label || seen[e.Text] {
+			continue
+		}
+		seen[e.Text] = true
+		items = append(items, CompletionItem{
+			Label: e.Text,
+			Kind:  CompletionKeyword,
+			TextEdit: &TextEdit{
+				Range:   Range{Start: doc.position(e.Line, e.Col), End: pos},
+				NewText: e.Text,
+			},
+		})
+	}
+	return items
+}
@@ -0,0 +1,325 @@
+package lsp
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/dottermi/x/combinator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// program parses lines like "let x = 1" and "fn f { let y = 2 }".
+func program() combinator.Parser[struct{}] {
+	name := combinator.EmitValue("name", combinator.Token())
+	let := combinator.Emit("let", combinator.Skip(combinator.Seq3(
+		combinator.Lexeme(combinator.Keyword("let")),
+		name,
+		combinator.Seq2(combinator.Symbol("="), combinator.IntToken()),
+	)))
+
+	var fn combinator.Parser[struct{}]
+	statement := combinator.Choice(let, combinator.Lazy(func() combinator.Parser[struct{}] { return fn }))
+	fn = combinator.Emit("fn", combinator.Skip(combinator.Seq3(
+		combinator.Lexeme(combinator.Keyword("fn")),
+		name,
+		combinator.Lexeme(combinator.Braces(combinator.Right(combinator.Spaces(), combinator.Many(statement)))),
+	)))
+
+	return combinator.Right(combinator.Spaces(), combinator.SkipMany(statement))
+}
+
+// client drives a server over in-process pipes.
+type client struct {
+	t      *testing.T
+	conn   *conn
+	nextID int
+	done   chan error
+}
+
+func newClient(t *testing.T, s *Server) *client {
+	t.Helper()
+	clientR, serverW := io.Pipe()
+	serverR, clientW := io.Pipe()
+
+	c := &client{t: t, conn: newConn(clientR, clientW), done: make(chan error, 1)}
+	go func() {
+		err := s.Serve(serverR, serverW)
+		serverW.Close()
+		c.done <- err
+	}()
+	t.Cleanup(func() {
+		clientW.Close()
+		<-c.done
+	})
+	return c
+}
+
+func (c *client) send(method string, params any) json.RawMessage {
+	c.t.Helper()
+	data, err := json.Marshal(params)
+	require.NoError(c.t, err)
+
+	c.nextID++
+	id := json.RawMessage(must(json.Marshal(c.nextID)))
+	require.NoError(c.t, c.conn.write(&message{ID: id, Method: method, Params: data}))
+
+	for {
+		msg, err := c.conn.read()
+		require.NoError(c.t, err)
+		if string(msg.ID) == string(id) {
+			if msg.Error != nil {
+				c.t.Fatalf("%s: %v", method, msg.Error)
+			}
+			return msg.Result
+		}
+	}
+}
+
+func (c *client) sendError(method string, params any) *responseError {
+	c.t.Helper()
+	data, err := json.Marshal(params)
+	require.NoError(c.t, err)
+
+	c.nextID++
+	id := json.RawMessage(must(json.Marshal(c.nextID)))
+	require.NoError(c.t, c.conn.write(&message{ID: id, Method: method, Params: data}))
+
+	msg, err := c.conn.read()
+	require.NoError(c.t, err)
+	require.NotNil(c.t, msg.Error)
+	return msg.Error
+}
+
+// notify sends a notification and returns the diagnostics published in reply.
+func (c *client) notify(method string, params any) []Diagnostic {
+	c.t.Helper()
+	data, err := json.Marshal(params)
+	require.NoError(c.t, err)
+	require.NoError(c.t, c.conn.write(&message{Method: method, Params: data}))
+
+	msg, err := c.conn.read()
+	require.NoError(c.t, err)
+	require.Equal(c.t, "textDocument/publishDiagnostics", msg.Method)
+
+	var published publishDiagnosticsParams
+	require.NoError(c.t, json.Unmarshal(msg.Params, &published))
+	return published.Diagnostics
+}
+
+func (c *client) open(uri, text string) []Diagnostic {
+	c.t.Helper()
+	return c.notify("textDocument/didOpen", map[string]any{
+		"textDocument": map[string]any{"uri": uri, "version": 1, "text": text},
+	})
+}
+
+func must[T any](v T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func initialized(t *testing.T) *client {
+	t.Helper()
+	c := newClient(t, New(program(),
+		WithName("toy"),
+		WithSymbol("fn", SymbolFunction),
+		WithSymbol("let", SymbolVariable),
+	))
+	c.send("initialize", map[string]any{})
+	return c
+}
+
+//nolint:paralleltest // subtests share one client
+func TestInitialize(t *testing.T) {
+	c := newClient(t, New(program(), WithName("toy")))
+
+	t.Run("should reject requests before initialize", func(t *testing.T) {
+		rpcErr := c.sendError("textDocument/documentSymbol", map[string]any{})
+		assert.Equal(t, codeNotInitialized, rpcErr.Code)
+	})
+
+	t.Run("should advertise capabilities", func(t *testing.T) {
+		var result struct {
+			Capabilities struct {
+				TextDocumentSync       int  `json:"textDocumentSync"`
+				DocumentSymbolProvider bool `json:"documentSymbolProvider"`
+			} `json:"capabilities"`
+			ServerInfo struct {
+				Name string `json:"name"`
+			} `json:"serverInfo"`
+		}
+		require.NoError(t, json.Unmarshal(c.send("initialize", map[string]any{}), &result))
+		assert.Equal(t, 1, result.Capabilities.TextDocumentSync)
+		assert.True(t, result.Capabilities.DocumentSymbolProvider)
+		assert.Equal(t, "toy", result.ServerInfo.Name)
+	})
+
+	t.Run("should report unknown methods", func(t *testing.T) {
+		rpcErr := c.sendError("workspace/unknown", map[string]any{})
+		assert.Equal(t, codeMethodNotFound, rpcErr.Code)
+	})
+
+	t.Run("should stop after shutdown and exit", func(t *testing.T) {
+		assert.Equal(t, "null", string(c.send("shutdown", nil)))
+		require.NoError(t, c.conn.write(&message{Method: "exit"}))
+		require.NoError(t, <-c.done)
+		c.done <- nil
+	})
+}
+
+func TestExitWithoutShutdown(t *testing.T) {
+	t.Parallel()
+
+	c := initialized(t)
+	require.NoError(t, c.conn.write(&message{Method: "exit"}))
+
+	err := <-c.done
+	c.done <- nil
+	assert.ErrorIs(t, err, ErrExitWithoutShutdown)
+}
+
+//nolint:paralleltest // subtests share one client
+func TestDiagnostics(t *testing.T) {
+	c := initialized(t)
+
+	t.Run("should publish no diagnostics for valid documents", func(t *testing.T) {
+		assert.Empty(t, c.open("file:///a.toy", "let x = 1\n"))
+	})
+
+	t.Run("should publish the parse error position", func(t *testing.T) {
+		diags := c.open("file:///b.toy", "let x = 1\nlet 😀 = 2\n")
+		require.Len(t, diags, 1)
+		assert.Equal(t, Range{Start: Position{Line: 1, Character: 0}, End: Position{Line: 1, Character: 10}}, diags[0].Range)
+		assert.Equal(t, SeverityError, diags[0].Severity)
+		assert.Equal(t, "toy", diags[0].Source)
+	})
+
+	t.Run("should count UTF-16 units in positions", func(t *testing.T) {
+		diags := c.open("file:///c.toy", "fn 😀f { let x = }")
+		require.Len(t, diags, 1)
+		assert.Equal(t, 0, diags[0].Range.Start.Line)
+		assert.Equal(t, 0, diags[0].Range.Start.Character)
+	})
+
+	t.Run("should update diagnostics on change", func(t *testing.T) {
+		diags := c.notify("textDocument/didChange", map[string]any{
+			"textDocument":   map[string]any{"uri": "file:///b.toy", "version": 2},
+			"contentChanges": []map[string]any{{"text": "let x = 1\nlet y = 2\n"}},
+		})
+		assert.Empty(t, diags)
+	})
+
+	t.Run("should clear diagnostics on close", func(t *testing.T) {
+		diags := c.notify("textDocument/didClose", map[string]any{
+			"textDocument": map[string]any{"uri": "file:///c.toy"},
+		})
+		assert.Empty(t, diags)
+	})
+}
+
+//nolint:paralleltest // subtests share one client
+func TestDocumentSymbols(t *testing.T) {
+	c := initialized(t)
+	require.Empty(t, c.open("file:///a.toy", "let x = 1\nfn main {\n  let y = 2\n}\n"))
+
+	var symbols []DocumentSymbol
+	params := map[string]any{"textDocument": map[string]any{"uri": "file:///a.toy"}}
+	require.NoError(t, json.Unmarshal(c.send("textDocument/documentSymbol", params), &symbols))
+
+	require.Len(t, symbols, 2)
+	assert.Equal(t, "x", symbols[0].Name)
+	assert.Equal(t, SymbolVariable, symbols[0].Kind)
+	assert.Equal(t, "let", symbols[0].Detail)
+
+	assert.Equal(t, "main", symbols[1].Name)
+	assert.Equal(t, SymbolFunction, symbols[1].Kind)
+	assert.Equal(t, Position{Line: 1, Character: 0}, symbols[1].Range.Start)
+	require.Len(t, symbols[1].Children, 1)
+	assert.Equal(t, "y", symbols[1].Children[0].Name)
+	assert.Equal(t, Position{Line: 2, Character: 2}, symbols[1].Children[0].Range.Start)
+
+	t.Run("should keep symbols while the document does not parse", func(t *testing.T) {
+		diags := c.notify("textDocument/didChange", map[string]any{
+			"textDocument":   map[string]any{"uri": "file:///a.toy", "version": 2},
+			"contentChanges": []map[string]any{{"text": "let x = 1\nfn main {\n  let y =\n}\n"}},
+		})
+		require.Len(t, diags, 1)
+
+		var again []DocumentSymbol
+		require.NoError(t, json.Unmarshal(c.send("textDocument/documentSymbol", params), &again))
+		assert.Equal(t, symbols, again)
+	})
+}
+
+func TestCompletion(t *testing.T) {
+	t.Parallel()
+
+	c := initialized(t)
+	c.open("file:///a.toy", "let x = 1\nf")
+
+	var items []CompletionItem
+	params := map[string]any{
+		"textDocument": map[string]any{"uri": "file:///a.toy"},
+		"position":     Position{Line: 1, Character: 1},
+	}
+	require.NoError(t, json.Unmarshal(c.send("textDocument/completion", params), &items))
+
+	require.Len(t, items, 1)
+	assert.Equal(t, "fn", items[0].Label)
+	assert.Equal(t, CompletionKeyword, items[0].Kind)
+	assert.Equal(t, &TextEdit{
+		Range:   Range{Start: Position{Line: 1, Character: 0}, End: Position{Line: 1, Character: 1}},
+		NewText: "fn",
+	}, items[0].TextEdit)
+}
+
+func TestDocument(t *testing.T) {
+	t.Parallel()
+
+	doc := newDocument("ab\r\n😀x\n")
+
+	t.Run("should convert rune columns to UTF-16 positions", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, Position{Line: 1, Character: 2}, doc.position(2, 2))
+		assert.Equal(t, Position{Line: 0, Character: 2}, doc.position(1, 3))
+	})
+
+	t.Run("should convert positions to byte offsets", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, 8, doc.offset(Position{Line: 1, Character: 2}))
+		assert.Equal(t, 2, doc.offset(Position{Line: 0, Character: 50}))
+		assert.Equal(t, len(doc.text), doc.offset(Position{Line: 9}))
+	})
+}
+
+func TestConn(t *testing.T) {
+	t.Parallel()
+
+	read := func(input string) (*message, error) {
+		return newConn(strings.NewReader(input), io.Discard).read()
+	}
+
+	t.Run("should read a framed message", func(t *testing.T) {
+		t.Parallel()
+		msg, err := read("Content-Length: 17\r\n\r\n{\"method\":\"exit\"}")
+		require.NoError(t, err)
+		assert.Equal(t, "exit", msg.Method)
+	})
+
+	t.Run("should reject negative lengths", func(t *testing.T) {
+		t.Parallel()
+		_, err := read("Content-Length: -1\r\n\r\n")
+		assert.ErrorContains(t, err, "invalid Content-Length")
+	})
+
+	t.Run("should reject oversized lengths before reading the body", func(t *testing.T) {
+		t.Parallel()
+		_, err := read("Content-Length: 9223372036854775807\r\n\r\n")
+		assert.ErrorContains(t, err, "exceeds")
+	})
+}
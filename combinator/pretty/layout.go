@@ -0,0 +1,168 @@
+package pretty
+
+import (
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// mode is the layout chosen for the group enclosing a document.
+type mode uint8
+
+const (
+	modeBreak mode = iota
+	modeFlat
+)
+
+// cmd is a document scheduled for printing with its indentation and mode.
+type cmd struct {
+	indent int
+	mode   mode
+	doc    Doc
+}
+
+// printer runs the layout algorithm, writing output as decisions are made.
+type printer struct {
+	w       io.Writer
+	width   int
+	col     int  // col is the current output column.
+	pending int  // pending is indentation owed before the next text.
+	newline bool // newline is true when indentation is still owed.
+	err     error
+}
+
+// layout prints d using a stack of pending commands, deciding each group by
+// checking whether its flat form fits in the rest of the current line.
+func (p *printer) layout(d Doc) {
+	stack := []cmd{{indent: 0, mode: modeBreak, doc: d}}
+
+	for len(stack) > 0 && p.err == nil {
+		c := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		switch c.doc.kind {
+		case kindNil:
+		case kindText:
+			p.text(c.doc.text)
+		case kindLine, kindSoftLine, kindHardLine:
+			p.line(c)
+		case kindConcat:
+			for i := len(c.doc.children) - 1; i >= 0; i-- {
+				stack = append(stack, cmd{indent: c.indent, mode: c.mode, doc: c.doc.children[i]})
+			}
+		case kindNest:
+			stack = append(stack, cmd{indent: c.indent + c.doc.indent, mode: c.mode, doc: c.doc.children[0]})
+		case kindAlign:
+			stack = append(stack, cmd{indent: p.column(), mode: c.mode, doc: c.doc.children[0]})
+		case kindGroup:
+			next := cmd{indent: c.indent, mode: modeFlat, doc: c.doc.children[0]}
+			if c.mode == modeBreak && !fits(p.width-p.column(), next, stack) {
+				next.mode = modeBreak
+			}
+			stack = append(stack, next)
+		case kindIfBreak:
+			chosen := c.doc.children[1]
+			if c.mode == modeBreak {
+				chosen = c.doc.children[0]
+			}
+			stack = append(stack, cmd{indent: c.indent, mode: c.mode, doc: chosen})
+		}
+	}
+}
+
+// column returns the column the next text will be printed at, counting
+// indentation that is still owed after a line break.
+func (p *printer) column() int {
+	if p.newline {
+		return p.pending
+	}
+	return p.col
+}
+
+func (p *printer) text(s string) {
+	if s == "" {
+		return
+	}
+	if p.newline {
+		p.write(strings.Repeat(" ", p.pending))
+		p.col = p.pending
+		p.newline = false
+	}
+	p.write(s)
+	p.col += utf8.RuneCountInString(s)
+}
+
+func (p *printer) line(c cmd) {
+	if c.mode == modeFlat {
+		switch c.doc.kind {
+		case kindLine:
+			p.text(" ")
+			return
+		case kindSoftLine:
+			return
+		}
+	}
+
+	p.write("\n")
+	p.col = 0
+	p.pending = c.indent
+	p.newline = true
+}
+
+func (p *printer) write(s string) {
+	if p.err == nil {
+		_, p.err = io.WriteString(p.w, s)
+	}
+}
+
+// fits reports whether next, followed by the remaining stack, can be printed
+// within width columns before the next line break in break mode.
+// A hard line inside the flat content never fits, forcing the group to break.
+func fits(width int, next cmd, rest []cmd) bool {
+	queue := []cmd{next}
+	restIdx := len(rest) - 1
+
+	for width >= 0 {
+		if len(queue) == 0 {
+			if restIdx < 0 {
+				return true
+			}
+			queue = append(queue, rest[restIdx])
+			restIdx--
+		}
+
+		c := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+
+		switch c.doc.kind {
+		case kindNil:
+		case kindText:
+			width -= utf8.RuneCountInString(c.doc.text)
+		case kindLine, kindSoftLine, kindHardLine:
+			if c.mode == modeBreak {
+				return true
+			}
+			if c.doc.kind == kindHardLine {
+				return false
+			}
+			if c.doc.kind == kindLine {
+				width--
+			}
+		case kindConcat:
+			for i := len(c.doc.children) - 1; i >= 0; i-- {
+				queue = append(queue, cmd{indent: c.indent, mode: c.mode, doc: c.doc.children[i]})
+			}
+		case kindNest, kindAlign, kindGroup:
+			// Indentation does not affect the width of the first line, and groups
+			// following the measured one are assumed to keep their enclosing mode.
+			queue = append(queue, cmd{indent: c.indent, mode: c.mode, doc: c.doc.children[0]})
+		case kindIfBreak:
+			chosen := c.doc.children[1]
+			if c.mode == modeBreak {
+				chosen = c.doc.children[0]
+			}
+			queue = append(queue, cmd{indent: c.indent, mode: c.mode, doc: chosen})
+		}
+	}
+	return false
+}
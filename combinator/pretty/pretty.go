@@ -0,0 +1,180 @@
+// Package pretty implements a Wadler/Leijen style pretty-printer: the printing
+// counterpart to parsing with the combinator package.
+//
+// Printers describe output as a [Doc] built from a small algebra of text, line
+// breaks, nesting and groups. The layout algorithm then picks, for every group,
+// whether it fits flat on the current line or must be broken, producing stable
+// output for a given page width.
+//
+//	// [1, 2, 3] when it fits, otherwise one element per line.
+//	func list(items []Doc) Doc {
+//		return pretty.Bracket("[", pretty.Join(pretty.Concat(pretty.Text(","), pretty.Line()), items), "]")
+//	}
+//
+//	out := pretty.Render(list(items), 80)
+//
+// # Building Blocks
+//
+//   - [Text] prints a literal string
+//   - [Line] breaks the line, or prints a space when its group is flat
+//   - [SoftLine] breaks the line, or prints nothing when its group is flat
+//   - [HardLine] always breaks the line, forcing enclosing groups to break
+//   - [Nest] indents the lines that break inside it
+//   - [Align] indents the lines inside it to the current column
+//   - [Group] lays out its content flat if it fits in the remaining width
+//   - [IfBreak] chooses between two documents depending on the group's layout
+package pretty
+
+import (
+	"io"
+	"strings"
+)
+
+// kind identifies the constructor of a [Doc].
+type kind uint8
+
+const (
+	kindNil kind = iota
+	kindText
+	kindLine
+	kindSoftLine
+	kindHardLine
+	kindConcat
+	kindNest
+	kindAlign
+	kindGroup
+	kindIfBreak
+)
+
+// Doc is an immutable document describing output with layout alternatives.
+// The zero Doc is empty and prints nothing.
+type Doc struct {
+	kind     kind
+	text     string
+	indent   int
+	children []Doc
+}
+
+// Nil returns the empty document.
+func Nil() Doc {
+	return Doc{}
+}
+
+// Text returns a document printing s literally.
+// Newlines in s become hard line breaks, so following lines are indented by
+// the enclosing [Nest].
+func Text(s string) Doc {
+	if !strings.Contains(s, "\n") {
+		return Doc{kind: kindText, text: s}
+	}
+
+	lines := strings.Split(s, "\n")
+	docs := make([]Doc, 0, 2*len(lines)-1)
+	for i, line := range lines {
+		if i > 0 {
+			docs = append(docs, HardLine())
+		}
+		docs = append(docs, Doc{kind: kindText, text: line})
+	}
+	return Concat(docs...)
+}
+
+// Line returns a line break that prints as a single space when its group fits flat.
+func Line() Doc {
+	return Doc{kind: kindLine}
+}
+
+// SoftLine returns a line break that prints as nothing when its group fits flat.
+func SoftLine() Doc {
+	return Doc{kind: kindSoftLine}
+}
+
+// HardLine returns a line break that is always printed.
+// Any group containing it is laid out broken.
+func HardLine() Doc {
+	return Doc{kind: kindHardLine}
+}
+
+// Concat returns the documents printed one after another.
+func Concat(docs ...Doc) Doc {
+	return Doc{kind: kindConcat, children: docs}
+}
+
+// Nest increases the indentation of line breaks inside d by indent columns.
+func Nest(indent int, d Doc) Doc {
+	return Doc{kind: kindNest, indent: indent, children: []Doc{d}}
+}
+
+// Align sets the indentation of line breaks inside d to the column where d starts.
+//
+// Example:
+//
+//	// let x = a
+//	//       + b
+//	Concat(Text("let x = "), Align(Concat(Text("a"), HardLine(), Text("+ b"))))
+func Align(d Doc) Doc {
+	return Doc{kind: kindAlign, children: []Doc{d}}
+}
+
+// Group lays out d flat, with every [Line] and [SoftLine] as a space or nothing,
+// when it fits in the remaining width; otherwise its line breaks are printed.
+// Nested groups are decided independently.
+func Group(d Doc) Doc {
+	return Doc{kind: kindGroup, children: []Doc{d}}
+}
+
+// IfBreak prints broken when the enclosing group is broken and flat otherwise.
+// Useful for trailing separators that only appear in multi-line layouts.
+//
+// Example:
+//
+//	trailingComma := IfBreak(Text(","), Nil())
+func IfBreak(broken, flat Doc) Doc {
+	return Doc{kind: kindIfBreak, children: []Doc{broken, flat}}
+}
+
+// Join returns docs separated by sep.
+//
+// Example:
+//
+//	Join(Concat(Text(","), Line()), []Doc{Text("a"), Text("b")}) // a, b
+func Join(sep Doc, docs []Doc) Doc {
+	parts := make([]Doc, 0, max(2*len(docs)-1, 0))
+	for i, d := range docs {
+		if i > 0 {
+			parts = append(parts, sep)
+		}
+		parts = append(parts, d)
+	}
+	return Concat(parts...)
+}
+
+// Bracket encloses d between open and close, laid out on one line if it fits,
+// otherwise with d indented by two columns on its own lines.
+//
+// Example:
+//
+//	Bracket("{", body, "}") // {body} or {\n  body\n}
+func Bracket(open string, d Doc, closing string) Doc {
+	return Group(Concat(
+		Text(open),
+		Nest(2, Concat(SoftLine(), d)),
+		SoftLine(),
+		Text(closing),
+	))
+}
+
+// Render lays out d for a page of the given width and returns the output.
+// Lines never end with trailing indentation.
+func Render(d Doc, width int) string {
+	var sb strings.Builder
+	_ = Write(&sb, d, width) // strings.Builder never fails
+	return sb.String()
+}
+
+// Write lays out d for a page of the given width and writes it to w.
+func Write(w io.Writer, d Doc, width int) error {
+	p := &printer{w: w, width: width}
+	p.layout(d)
+	return p.err
+}
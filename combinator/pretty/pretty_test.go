@@ -0,0 +1,237 @@
+package pretty
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dottermi/x/combinator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func list(items ...string) Doc {
+	docs := make([]Doc, len(items))
+	for i, item := range items {
+		docs[i] = Text(item)
+	}
+	return Bracket("[", Join(Concat(Text(","), Line()), docs), "]")
+}
+
+func TestText(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should print literal text", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "hello", Render(Text("hello"), 80))
+	})
+
+	t.Run("should turn newlines into indented hard lines", func(t *testing.T) {
+		t.Parallel()
+		doc := Nest(2, Concat(Text("a"), Text("\nb\nc")))
+		assert.Equal(t, "a\n  b\n  c", Render(doc, 80))
+	})
+
+	t.Run("should print nothing for the zero document", func(t *testing.T) {
+		t.Parallel()
+		assert.Empty(t, Render(Doc{}, 80))
+		assert.Empty(t, Render(Nil(), 80))
+	})
+}
+
+func TestGroup(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should lay out flat when it fits", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "[1, 2, 3]", Render(list("1", "2", "3"), 80))
+	})
+
+	t.Run("should break when it does not fit", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "[\n  1,\n  2,\n  3\n]", Render(list("1", "2", "3"), 8))
+	})
+
+	t.Run("should fit exactly at the page width", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "[1, 2, 3]", Render(list("1", "2", "3"), 9))
+	})
+
+	t.Run("should decide nested groups independently", func(t *testing.T) {
+		t.Parallel()
+		doc := Bracket("[", Join(Concat(Text(","), Line()), []Doc{
+			list("1", "2"),
+			list("3", "4"),
+		}), "]")
+		assert.Equal(t, "[\n  [1, 2],\n  [3, 4]\n]", Render(doc, 12))
+	})
+
+	t.Run("should count text following the group", func(t *testing.T) {
+		t.Parallel()
+		doc := Concat(list("1", "2"), Text(" + rest"))
+		assert.Equal(t, "[1, 2] + rest", Render(doc, 13))
+		assert.Equal(t, "[\n  1,\n  2\n] + rest", Render(doc, 12))
+	})
+
+	t.Run("should break groups containing a hard line", func(t *testing.T) {
+		t.Parallel()
+		doc := Group(Concat(Text("a"), Line(), Text("b"), HardLine(), Text("c")))
+		assert.Equal(t, "a\nb\nc", Render(doc, 80))
+	})
+
+	t.Run("should measure width in runes", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "[ä, ö]", Render(list("ä", "ö"), 6))
+	})
+}
+
+func TestLines(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should print soft lines as nothing when flat", func(t *testing.T) {
+		t.Parallel()
+		doc := Group(Concat(Text("a"), SoftLine(), Text("b")))
+		assert.Equal(t, "ab", Render(doc, 80))
+	})
+
+	t.Run("should break lines outside any group", func(t *testing.T) {
+		t.Parallel()
+		doc := Concat(Text("a"), Line(), Text("b"))
+		assert.Equal(t, "a\nb", Render(doc, 80))
+	})
+
+	t.Run("should not leave indentation on empty lines", func(t *testing.T) {
+		t.Parallel()
+		doc := Nest(4, Concat(Text("a"), HardLine(), HardLine(), Text("b")))
+		assert.Equal(t, "a\n\n    b", Render(doc, 80))
+	})
+}
+
+func TestAlign(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should indent to the current column", func(t *testing.T) {
+		t.Parallel()
+		doc := Concat(Text("let x = "), Align(Concat(Text("a"), HardLine(), Text("+ b"))))
+		assert.Equal(t, "let x = a\n        + b", Render(doc, 80))
+	})
+}
+
+func TestIfBreak(t *testing.T) {
+	t.Parallel()
+
+	trailing := func(items ...string) Doc {
+		docs := make([]Doc, len(items))
+		for i, item := range items {
+			docs[i] = Text(item)
+		}
+		body := Concat(Join(Concat(Text(","), Line()), docs), IfBreak(Text(","), Nil()))
+		return Bracket("{", body, "}")
+	}
+
+	t.Run("should print the flat variant when the group fits", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "{a, b}", Render(trailing("a", "b"), 80))
+	})
+
+	t.Run("should print the broken variant when the group breaks", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, "{\n  a,\n  b,\n}", Render(trailing("a", "b"), 4))
+	})
+}
+
+func TestJoin(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should separate documents", func(t *testing.T) {
+		t.Parallel()
+		doc := Join(Text(", "), []Doc{Text("a"), Text("b"), Text("c")})
+		assert.Equal(t, "a, b, c", Render(doc, 80))
+	})
+
+	t.Run("should return empty document for no documents", func(t *testing.T) {
+		t.Parallel()
+		assert.Empty(t, Render(Join(Text(", "), nil), 80))
+	})
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should write the layout", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		require.NoError(t, Write(&buf, list("1", "2"), 80))
+		assert.Equal(t, "[1, 2]", buf.String())
+	})
+
+	t.Run("should return writer errors", func(t *testing.T) {
+		t.Parallel()
+		err := Write(failingWriter{}, list("1", "2"), 80)
+		assert.EqualError(t, err, "disk full")
+	})
+}
+
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	// A nested list grammar parsed with combinator and printed back with pretty.
+	type node struct {
+		atom  string
+		items []node
+	}
+
+	var value combinator.Parser[node]
+	atom := combinator.Map(combinator.Lexeme(combinator.Ident()), func(s string) node {
+		return node{atom: s}
+	})
+	items := combinator.Lazy(func() combinator.Parser[node] {
+		return combinator.Map(
+			combinator.Between(combinator.Symbol("["), combinator.Symbol("]"), combinator.SepBy(value, combinator.Symbol(","))),
+			func(items []node) node { return node{items: items} },
+		)
+	})
+	value = combinator.Choice(atom, items)
+
+	var show func(n node) Doc
+	show = func(n node) Doc {
+		if n.items == nil {
+			return Text(n.atom)
+		}
+		docs := make([]Doc, len(n.items))
+		for i, item := range n.items {
+			docs[i] = show(item)
+		}
+		return Bracket("[", Join(Concat(Text(","), Line()), docs), "]")
+	}
+
+	input := "[alpha, [beta, gamma], [delta, [epsilon, zeta]], eta]"
+	parsed := combinator.Parse(value, input)
+	require.True(t, parsed.OK, "%v", parsed.Err)
+
+	for _, width := range []int{80, 40, 20, 16} {
+		out := Render(show(parsed.Value), width)
+		for _, line := range strings.Split(out, "\n") {
+			assert.LessOrEqual(t, len(line), width, "line %q exceeds width %d", line, width)
+		}
+
+		reparsed := combinator.Parse(value, out)
+		require.True(t, reparsed.OK, "%v", reparsed.Err)
+		assert.Equal(t, parsed.Value, reparsed.Value, "width %d", width)
+	}
+
+	assert.Equal(t, input, Render(show(parsed.Value), 80))
+	assert.Equal(t, `[
+  alpha,
+  [beta, gamma],
+  [delta, [epsilon, zeta]],
+  eta
+]`, Render(show(parsed.Value), 30))
+}
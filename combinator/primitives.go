@@ -1,9 +1,6 @@
 package combinator
 
-import (
-	"fmt"
-	"slices"
-)
+import "slices"
 
 // Char matches a single specific character and returns it as a rune.
 // Fails with an error message showing the expected and actual characters.
@@ -13,13 +10,16 @@ import (
 //	result := Parse(Char('a'), "abc")
 //	// result.Value == 'a'
 func Char(r rune) Parser[rune] {
+	want := string(r)
+
 	return func(state State) Result[rune] {
 		if state.IsEOF() {
-			return Failure[rune](fmt.Errorf("unexpected EOF, expected '%c' at line %d, col %d", r, state.Line, state.Col), state)
+			state.expect(want, false)
+			return Failure[rune](newError(errExpectedAtEOF, want, state), state)
 		}
 
 		if state.Current() != r {
-			return Failure[rune](fmt.Errorf("expected '%c', got '%c' at line %d, col %d", r, state.Current(), state.Line, state.Col), state)
+			return Failure[rune](newError(errMismatch, want, state), state)
 		}
 
 		return Success(r, state.Advance())
@@ -34,22 +34,23 @@ func Char(r rune) Parser[rune] {
 //	result := Parse(String("hello"), "hello world")
 //	// result.Value == "hello"
 func String(s string) Parser[string] {
+	runes := []rune(s)
+
 	return func(state State) Result[string] {
-		current := state
+		rest := state.Input[state.Pos:]
 
-		for _, r := range s {
-			if current.IsEOF() {
-				return Failure[string](fmt.Errorf("unexpected EOF, expected '%s' at line %d, col %d", s, state.Line, state.Col), state)
+		for i, r := range runes {
+			if i >= len(rest) {
+				state.expect(s, false)
+				return Failure[string](newError(errExpectedAtEOF, s, state), state)
 			}
 
-			if current.Current() != r {
-				return Failure[string](fmt.Errorf("expected '%s' at line %d, col %d", s, state.Line, state.Col), state)
+			if rest[i] != r {
+				return Failure[string](newError(errExpected, s, state), state)
 			}
-
-			current = current.Advance()
 		}
 
-		return Success(s, current)
+		return Success(s, state.advanceTo(state.Pos+len(runes)))
 	}
 }
 
@@ -67,12 +68,12 @@ func String(s string) Parser[string] {
 func Satisfy(pred func(rune) bool) Parser[rune] {
 	return func(state State) Result[rune] {
 		if state.IsEOF() {
-			return Failure[rune](fmt.Errorf("unexpected EOF at line %d, col %d", state.Line, state.Col), state)
+			return Failure[rune](newError(errUnexpectedEOF, "", state), state)
 		}
 
 		r := state.Current()
 		if !pred(r) {
-			return Failure[rune](fmt.Errorf("unexpected '%c' at line %d, col %d", r, state.Line, state.Col), state)
+			return Failure[rune](newError(errUnexpected, "", state), state)
 		}
 
 		return Success(r, state.Advance())
@@ -84,7 +85,7 @@ func Satisfy(pred func(rune) bool) Parser[rune] {
 func Any() Parser[rune] {
 	return func(state State) Result[rune] {
 		if state.IsEOF() {
-			return Failure[rune](fmt.Errorf("unexpected EOF at line %d, col %d", state.Line, state.Col), state)
+			return Failure[rune](newError(errUnexpectedEOF, "", state), state)
 		}
 
 		return Success(state.Current(), state.Advance())
@@ -100,13 +101,7 @@ func Any() Parser[rune] {
 //	result := Parse(complete, "42")    // succeeds
 //	result = Parse(complete, "42abc")  // fails
 func EOF() Parser[struct{}] {
-	return func(state State) Result[struct{}] {
-		if !state.IsEOF() {
-			return Failure[struct{}](fmt.Errorf("expected EOF, got '%c' at line %d, col %d", state.Current(), state.Line, state.Col), state)
-		}
-
-		return Success(struct{}{}, state)
-	}
+	return EndOfInput[rune]()
 }
 
 // OneOf matches any single character that appears in the provided string.
@@ -153,3 +148,72 @@ func Range(from, to rune) Parser[rune] {
 		return r >= from && r <= to
 	})
 }
+
+// TakeWhile matches zero or more characters satisfying the predicate.
+// Returns the matched text as a slice of the input, without building it rune by rune.
+// Always succeeds (returns empty string if the first character does not match).
+//
+// Example:
+//
+//	word := TakeWhile(unicode.IsLetter)
+//	result := Parse(word, "hello world")
+//	// result.Value == "hello"
+func TakeWhile(pred func(rune) bool) Parser[string] {
+	return func(state State) Result[string] {
+		next := state.advanceTo(state.scan(state.Pos, pred))
+		return Success(state.text(next), next)
+	}
+}
+
+// TakeWhile1 matches one or more characters satisfying the predicate.
+// Returns the matched text as a slice of the input.
+// Fails like [Satisfy] if the first character does not match.
+//
+// Example:
+//
+//	digits := TakeWhile1(unicode.IsDigit)
+//	result := Parse(digits, "2024-01-01")
+//	// result.Value == "2024"
+func TakeWhile1(pred func(rune) bool) Parser[string] {
+	first := Satisfy(pred)
+
+	return func(state State) Result[string] {
+		end := state.scan(state.Pos, pred)
+		if end == state.Pos {
+			r := first(state)
+			return Failure[string](r.Err, r.State)
+		}
+
+		next := state.advanceTo(end)
+		return Success(state.text(next), next)
+	}
+}
+
+// TakeUntil matches characters up to the first position where the terminator matches.
+// Returns the text before the terminator; the terminator itself is not consumed.
+// Fails with the terminator's error if it never matches before the end of input.
+//
+// Example:
+//
+//	comment := Between(String("/*"), String("*/"), TakeUntil(String("*/")))
+//	result := Parse(comment, "/* note */")
+//	// result.Value == " note "
+func TakeUntil[T any](terminator Parser[T]) Parser[string] {
+	return func(state State) Result[string] {
+		current := state
+		for {
+			if err := current.checkpoint(); err != nil {
+				return Failure[string](err, current)
+			}
+
+			r := terminator(current)
+			if r.OK {
+				return Success(state.text(current), current)
+			}
+			if current.IsEOF() {
+				return Failure[string](r.Err, current)
+			}
+			current = current.Advance()
+		}
+	}
+}
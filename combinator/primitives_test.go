@@ -2,6 +2,7 @@ package combinator
 
 import (
 	"testing"
+	"unicode"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -153,3 +154,80 @@ func TestRange(t *testing.T) {
 		assert.False(t, result.OK)
 	})
 }
+
+//nolint:paralleltest // tests share parser state
+func TestTakeWhile(t *testing.T) {
+	t.Run("should return matching prefix", func(t *testing.T) {
+		result := Parse(TakeWhile(unicode.IsLetter), "hello world")
+		assert.True(t, result.OK)
+		assert.Equal(t, "hello", result.Value)
+		assert.Equal(t, 5, result.State.Pos)
+	})
+
+	t.Run("should succeed with empty match", func(t *testing.T) {
+		result := Parse(TakeWhile(unicode.IsDigit), "abc")
+		assert.True(t, result.OK)
+		assert.Empty(t, result.Value)
+		assert.Equal(t, 0, result.State.Pos)
+	})
+
+	t.Run("should track lines", func(t *testing.T) {
+		result := Parse(TakeWhile(unicode.IsSpace), " \n\n  x")
+		assert.Equal(t, 3, result.State.Line)
+		assert.Equal(t, 3, result.State.Col)
+	})
+
+	t.Run("should handle unicode", func(t *testing.T) {
+		result := Parse(TakeWhile(unicode.IsLetter), "café!")
+		assert.Equal(t, "café", result.Value)
+		assert.Equal(t, 4, result.State.Pos)
+	})
+}
+
+//nolint:paralleltest // tests share parser state
+func TestTakeWhile1(t *testing.T) {
+	t.Run("should return matching prefix", func(t *testing.T) {
+		result := Parse(TakeWhile1(unicode.IsDigit), "2024-01")
+		assert.True(t, result.OK)
+		assert.Equal(t, "2024", result.Value)
+	})
+
+	t.Run("should fail without a match", func(t *testing.T) {
+		result := Parse(TakeWhile1(unicode.IsDigit), "x1")
+		assert.False(t, result.OK)
+		assert.Contains(t, result.Err.Error(), "unexpected 'x'")
+	})
+
+	t.Run("should fail on EOF", func(t *testing.T) {
+		assert.False(t, Parse(TakeWhile1(unicode.IsDigit), "").OK)
+	})
+}
+
+//nolint:paralleltest // tests share parser state
+func TestTakeUntil(t *testing.T) {
+	t.Run("should stop before terminator", func(t *testing.T) {
+		result := Parse(TakeUntil(String("*/")), " note */ rest")
+		assert.True(t, result.OK)
+		assert.Equal(t, " note ", result.Value)
+		assert.Equal(t, 6, result.State.Pos)
+	})
+
+	t.Run("should return empty text when terminator is first", func(t *testing.T) {
+		result := Parse(TakeUntil(Char(';')), ";")
+		assert.True(t, result.OK)
+		assert.Empty(t, result.Value)
+	})
+
+	t.Run("should fail when terminator never matches", func(t *testing.T) {
+		result := Parse(TakeUntil(String("*/")), "unterminated")
+		assert.False(t, result.OK)
+		assert.Contains(t, result.Err.Error(), "unexpected EOF")
+	})
+
+	t.Run("should compose with delimiters", func(t *testing.T) {
+		comment := Between(String("/*"), String("*/"), TakeUntil(String("*/")))
+		result := Parse(comment, "/* a * b */")
+		assert.True(t, result.OK)
+		assert.Equal(t, " a * b ", result.Value)
+	})
+}
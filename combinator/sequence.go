@@ -1,7 +1,5 @@
 package combinator
 
-import "fmt"
-
 // Pair holds two values of potentially different types.
 type Pair[A, B any] struct {
 	First  A
@@ -24,8 +22,8 @@ type Triple[A, B, C any] struct {
 //	ab := Seq2(Char('a'), Char('b'))
 //	result := Parse(ab, "ab")
 //	// result.Value == Pair[rune, rune]{First: 'a', Second: 'b'}
-func Seq2[A, B any](p1 Parser[A], p2 Parser[B]) Parser[Pair[A, B]] {
-	return func(state State) Result[Pair[A, B]] {
+func Seq2[A, B, I any](p1 GParser[A, I], p2 GParser[B, I]) GParser[Pair[A, B], I] {
+	return func(state GState[I]) GResult[Pair[A, B], I] {
 		r1 := p1(state)
 		if !r1.OK {
 			return Failure[Pair[A, B]](r1.Err, r1.State)
@@ -40,8 +38,8 @@ func Seq2[A, B any](p1 Parser[A], p2 Parser[B]) Parser[Pair[A, B]] {
 
 // Seq3 runs three parsers in sequence and returns a Triple of results.
 // Fails immediately if any parser fails.
-func Seq3[A, B, C any](p1 Parser[A], p2 Parser[B], p3 Parser[C]) Parser[Triple[A, B, C]] {
-	return func(state State) Result[Triple[A, B, C]] {
+func Seq3[A, B, C, I any](p1 GParser[A, I], p2 GParser[B, I], p3 GParser[C, I]) GParser[Triple[A, B, C], I] {
+	return func(state GState[I]) GResult[Triple[A, B, C], I] {
 		r1 := p1(state)
 		if !r1.OK {
 			return Failure[Triple[A, B, C]](r1.Err, r1.State)
@@ -68,13 +66,13 @@ func Seq3[A, B, C any](p1 Parser[A], p2 Parser[B], p3 Parser[C]) Parser[Triple[A
 //	boolean := Choice(String("true"), String("false"))
 //	result := Parse(boolean, "true")
 //	// result.Value == "true"
-func Choice[T any](parsers ...Parser[T]) Parser[T] {
-	return func(state State) Result[T] {
+func Choice[T, I any](parsers ...GParser[T, I]) GParser[T, I] {
+	return func(state GState[I]) GResult[T, I] {
 		var lastErr error
 
 		for _, p := range parsers {
 			r := p(state)
-			if r.OK {
+			if r.OK || state.aborted() {
 				return r
 			}
 			lastErr = r.Err
@@ -83,7 +81,7 @@ func Choice[T any](parsers ...Parser[T]) Parser[T] {
 		if lastErr != nil {
 			return Failure[T](lastErr, state)
 		}
-		return Failure[T](fmt.Errorf("no alternatives matched at line %d, col %d", state.Line, state.Col), state)
+		return Failure[T](newError(errNoAlternatives, "", state), state)
 	}
 }
 
@@ -96,12 +94,16 @@ func Choice[T any](parsers ...Parser[T]) Parser[T] {
 //	digits := Many(Digit())
 //	result := Parse(digits, "123abc")
 //	// result.Value == []rune{'1', '2', '3'}
-func Many[T any](p Parser[T]) Parser[[]T] {
-	return func(state State) Result[[]T] {
+func Many[T, I any](p GParser[T, I]) GParser[[]T, I] {
+	return func(state GState[I]) GResult[[]T, I] {
 		var values []T
 		current := state
 
 		for {
+			if err := current.checkpoint(); err != nil {
+				return Failure[[]T](err, current)
+			}
+
 			r := p(current)
 			if !r.OK {
 				break
@@ -128,8 +130,8 @@ func Many[T any](p Parser[T]) Parser[[]T] {
 //	digits := Many1(Digit())
 //	result := Parse(digits, "123")
 //	// result.Value == []rune{'1', '2', '3'}
-func Many1[T any](p Parser[T]) Parser[[]T] {
-	return func(state State) Result[[]T] {
+func Many1[T, I any](p GParser[T, I]) GParser[[]T, I] {
+	return func(state GState[I]) GResult[[]T, I] {
 		first := p(state)
 		if !first.OK {
 			return Failure[[]T](first.Err, first.State)
@@ -150,8 +152,8 @@ func Many1[T any](p Parser[T]) Parser[[]T] {
 //	sign := Opt(Char('-'))
 //	result := Parse(sign, "42")  // result.Value == nil
 //	result = Parse(sign, "-42") // result.Value == ptr to '-'
-func Opt[T any](p Parser[T]) Parser[*T] {
-	return func(state State) Result[*T] {
+func Opt[T, I any](p GParser[T, I]) GParser[*T, I] {
+	return func(state GState[I]) GResult[*T, I] {
 		r := p(state)
 		if r.OK {
 			return Success(&r.Value, r.State)
@@ -162,8 +164,8 @@ func Opt[T any](p Parser[T]) Parser[*T] {
 
 // And sequences two parsers, returning only the second parser's result.
 // Both parsers must succeed.
-func And[A, B any](p1 Parser[A], p2 Parser[B]) Parser[B] {
-	return func(state State) Result[B] {
+func And[A, B, I any](p1 GParser[A, I], p2 GParser[B, I]) GParser[B, I] {
+	return func(state GState[I]) GResult[B, I] {
 		r1 := p1(state)
 		if !r1.OK {
 			return Failure[B](r1.Err, r1.State)
@@ -179,8 +181,8 @@ func And[A, B any](p1 Parser[A], p2 Parser[B]) Parser[B] {
 //
 //	// Match a number followed by a semicolon, keep only the number
 //	num := Left(Integer(), Char(';'))
-func Left[A, B any](p1 Parser[A], p2 Parser[B]) Parser[A] {
-	return func(state State) Result[A] {
+func Left[A, B, I any](p1 GParser[A, I], p2 GParser[B, I]) GParser[A, I] {
+	return func(state GState[I]) GResult[A, I] {
 		r1 := p1(state)
 		if !r1.OK {
 			return r1
@@ -200,8 +202,8 @@ func Left[A, B any](p1 Parser[A], p2 Parser[B]) Parser[A] {
 //
 //	// Skip whitespace before a number
 //	num := Right(Spaces(), Integer())
-func Right[A, B any](p1 Parser[A], p2 Parser[B]) Parser[B] {
-	return func(state State) Result[B] {
+func Right[A, B, I any](p1 GParser[A, I], p2 GParser[B, I]) GParser[B, I] {
+	return func(state GState[I]) GResult[B, I] {
 		r1 := p1(state)
 		if !r1.OK {
 			return Failure[B](r1.Err, r1.State)
@@ -217,8 +219,8 @@ func Right[A, B any](p1 Parser[A], p2 Parser[B]) Parser[B] {
 // Example:
 //
 //	hex := Count(2, HexDigit()) // match exactly 2 hex digits
-func Count[T any](n int, p Parser[T]) Parser[[]T] {
-	return func(state State) Result[[]T] {
+func Count[T, I any](n int, p GParser[T, I]) GParser[[]T, I] {
+	return func(state GState[I]) GResult[[]T, I] {
 		var values []T
 		current := state
 
@@ -0,0 +1,178 @@
+package combinator
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf16"
+)
+
+// ErrIncludeCycle is reported when [Include] would load a source that is
+// already being parsed further up the include chain.
+var ErrIncludeCycle = errors.New("include cycle")
+
+// source names the input a State reads from and the source that included it.
+type source struct {
+	name   string
+	parent *source
+}
+
+// Position describes a location in a named source in the units used by
+// editors and tools.
+type Position struct {
+	File   string // File is the source name given to [NewSourceState], or empty.
+	Offset int    // Offset is the byte offset in the UTF-8 source.
+	Rune   int    // Rune is the rune offset, the same as State.Pos.
+	Line   int    // Line is the line number (1-indexed).
+	Col    int    // Col is the column in runes (1-indexed).
+	Col16  int    // Col16 is the column in UTF-16 code units (1-indexed), as counted by LSP clients.
+}
+
+// String formats the position as file:line:col, or line:col without a file name.
+func (p Position) String() string {
+	if p.File == "" {
+		return fmt.Sprintf("%d:%d", p.Line, p.Col)
+	}
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Col)
+}
+
+// NewSourceState creates a parser state for input read from the named source,
+// such as a file path. Errors produced while parsing it mention the name.
+//
+// Example:
+//
+//	state := NewSourceState("app.conf", data)
+//	fmt.Println(state.Position()) // app.conf:1:1
+func NewSourceState(name, input string) State {
+	state := NewState(input)
+	state.source = &source{name: name}
+	return state
+}
+
+// ParseSource runs a parser on input read from the named source.
+// Like [Parse], but errors report the source name along with the position.
+//
+// Example:
+//
+//	result := ParseSource(config, "app.conf", data)
+//	// result.Err: expected '=' at line 3, col 7 in app.conf
+func ParseSource[T any](p Parser[T], name, input string, opts ...Option) Result[T] {
+	return run(p, NewSourceState(name, input), opts)
+}
+
+// Source returns the name of the source s reads from, or "" for anonymous input.
+func (s GState[I]) Source() string {
+	if s.source == nil {
+		return ""
+	}
+	return s.source.name
+}
+
+// Position returns the location of s, including its byte offset and UTF-16 column.
+// For input that is not text, Offset and Col16 count elements like Rune and Col.
+func (s GState[I]) Position() Position {
+	col16 := s.Col
+	if runes, ok := any(s.Input).([]rune); ok {
+		col16 = 1
+		for i := s.Pos - 1; i >= 0 && runes[i] != '\n'; i-- {
+			col16 += utf16.RuneLen(runes[i])
+		}
+	}
+
+	return Position{
+		File:   s.Source(),
+		Offset: s.off,
+		Rune:   s.Pos,
+		Line:   s.Line,
+		Col:    s.Col,
+		Col16:  col16,
+	}
+}
+
+// at describes the location of s for error messages.
+func (s GState[I]) at() string {
+	if name := s.Source(); name != "" {
+		return fmt.Sprintf("at line %d, col %d in %s", s.Line, s.Col, name)
+	}
+	return fmt.Sprintf("at line %d, col %d", s.Line, s.Col)
+}
+
+// Loader returns the content of the source with the given name.
+type Loader func(name string) (string, error)
+
+// Include splices another source into the parse. It runs directive to read the
+// name of the source, loads it with load and parses its whole content with p,
+// then continues after the directive. Positions and errors inside the included
+// content refer to the included source, so a failure reports the right file.
+//
+// Once the directive matches, a failure to load or parse the included source
+// aborts the whole parse, so alternatives tried afterwards cannot hide it.
+// Aborting needs the parse to be started by [ParseSource] or with options;
+// under a plain [Parse] the failure is returned like any other.
+// Including a source that is already being parsed further up the chain fails
+// with [ErrIncludeCycle]. Each inclusion counts toward the limits set by
+// [WithMaxSteps] and [WithMaxDepth].
+//
+// Example:
+//
+//	load := func(name string) (string, error) {
+//		data, err := os.ReadFile(name)
+//		return string(data), err
+//	}
+//	var config Rule[[]Setting]
+//	config = func() Parser[[]Setting] {
+//		directive := Right(Symbol("include"), Lexeme(StringLit()))
+//		return Map(Many(Choice(Include(directive, load, Ref(&config)), setting)), flatten)
+//	}
+func Include[T any](directive Parser[string], load Loader, p Parser[T]) Parser[T] {
+	return func(state State) Result[T] {
+		d := directive(state)
+		if !d.OK {
+			return Failure[T](d.Err, d.State)
+		}
+
+		// Once the directive matched, failures are reported as they are instead of
+		// letting other alternatives hide where the included source went wrong.
+		fail := func(err error) Result[T] {
+			if state.session != nil {
+				state.session.abort(err)
+			}
+			return Failure[T](err, state)
+		}
+
+		name := d.Value
+		for src := state.source; src != nil; src = src.parent {
+			if src.name == name {
+				return fail(fmt.Errorf("%w: %s includes itself %s", ErrIncludeCycle, name, state.at()))
+			}
+		}
+
+		content, err := load(name)
+		if err != nil {
+			return fail(fmt.Errorf("include %q %s: %w", name, state.at(), err))
+		}
+
+		if err := state.enter(); err != nil {
+			return Failure[T](err, state)
+		}
+		inner := NewState(content)
+		inner.source = &source{name: name, parent: state.source}
+		inner.session = state.session
+		inner.events = d.State.events
+
+		r := p(inner)
+		state.leave()
+		if state.aborted() {
+			return Failure[T](state.session.err, state)
+		}
+		if !r.OK {
+			return fail(r.Err)
+		}
+		if !r.State.IsEOF() {
+			return fail(newError(errExpectedEOF, "", r.State))
+		}
+
+		next := d.State
+		next.events = r.State.events
+		return Success(r.Value, next)
+	}
+}
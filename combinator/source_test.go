@@ -0,0 +1,109 @@
+package combinator
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// settings parses "key = value" lines and "include name" directives, loading
+// included sources from files.
+func settings(files map[string]string) Parser[[]string] {
+	load := func(name string) (string, error) {
+		content, ok := files[name]
+		if !ok {
+			return "", errors.New("no such file")
+		}
+		return content, nil
+	}
+
+	var config Rule[[]string]
+	config = func() Parser[[]string] {
+		value := Lexeme(TakeWhile1(func(r rune) bool { return r != '\n' && r != ' ' }))
+		setting := Map(Seq3(Token(), Symbol("="), value), func(t Triple[string, string, string]) []string {
+			return []string{t.First + "=" + t.Third}
+		})
+		directive := Right(Symbol("include"), Token())
+		entry := Choice(Include(directive, load, Ref(&config)), setting)
+
+		return Right(Spaces(), Map(Many(entry), func(groups [][]string) []string {
+			var all []string
+			for _, g := range groups {
+				all = append(all, g...)
+			}
+			return all
+		}))
+	}
+	return Left(Ref(&config), EOF())
+}
+
+//nolint:paralleltest // tests share parser state
+func TestPosition(t *testing.T) {
+	t.Run("should report byte, rune and UTF-16 offsets", func(t *testing.T) {
+		state := NewSourceState("emoji.txt", "x\n😀é!").AdvanceN(4)
+		assert.Equal(t, Position{File: "emoji.txt", Offset: 8, Rune: 4, Line: 2, Col: 3, Col16: 4}, state.Position())
+	})
+
+	t.Run("should format as file:line:col", func(t *testing.T) {
+		assert.Equal(t, "app.conf:2:3", Position{File: "app.conf", Line: 2, Col: 3}.String())
+		assert.Equal(t, "2:3", Position{Line: 2, Col: 3}.String())
+	})
+
+	t.Run("should be anonymous for NewState", func(t *testing.T) {
+		assert.Empty(t, NewState("x").Source())
+		assert.Equal(t, "app.conf", NewSourceState("app.conf", "x").Source())
+	})
+}
+
+//nolint:paralleltest // tests share parser state
+func TestParseSource(t *testing.T) {
+	t.Run("should name the source in errors", func(t *testing.T) {
+		result := ParseSource(Char('a'), "app.conf", "b")
+		require.False(t, result.OK)
+		assert.EqualError(t, result.Err, "expected 'a', got 'b' at line 1, col 1 in app.conf")
+	})
+
+	t.Run("should keep anonymous error messages unchanged", func(t *testing.T) {
+		result := Parse(Char('a'), "b")
+		assert.EqualError(t, result.Err, "expected 'a', got 'b' at line 1, col 1")
+	})
+}
+
+//nolint:paralleltest // tests share parser state
+func TestInclude(t *testing.T) {
+	t.Run("should splice included sources", func(t *testing.T) {
+		files := map[string]string{
+			"db":   "host = local\ninclude auth\n",
+			"auth": "user = admin",
+		}
+		result := ParseSource(settings(files), "main", "name = app\ninclude db\nport = 80")
+		require.True(t, result.OK, "%v", result.Err)
+		assert.Equal(t, []string{"name=app", "host=local", "user=admin", "port=80"}, result.Value)
+		assert.Equal(t, "main", result.State.Source())
+		assert.Equal(t, 3, result.State.Line)
+	})
+
+	t.Run("should report errors in the included source", func(t *testing.T) {
+		files := map[string]string{"db": "host = local\nport 80\n"}
+		result := ParseSource(settings(files), "main", "include db\nname = app")
+		require.False(t, result.OK)
+		assert.EqualError(t, result.Err, "expected EOF, got 'p' at line 2, col 1 in db")
+	})
+
+	t.Run("should report load failures", func(t *testing.T) {
+		result := ParseSource(settings(nil), "main", "name = app\ninclude missing")
+		require.False(t, result.OK)
+		assert.EqualError(t, result.Err, `include "missing" at line 2, col 1 in main: no such file`)
+	})
+
+	t.Run("should detect include cycles", func(t *testing.T) {
+		files := map[string]string{"a": "include b", "b": "include a"}
+		result := ParseSource(settings(files), "main", "include a")
+		require.False(t, result.OK)
+		require.ErrorIs(t, result.Err, ErrIncludeCycle)
+		assert.Contains(t, fmt.Sprint(result.Err), "in b")
+	})
+}
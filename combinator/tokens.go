@@ -0,0 +1,219 @@
+package combinator
+
+import (
+	"slices"
+	"strings"
+)
+
+// trie maps literal strings to values for single-pass longest-match lookup.
+type trie[T any] struct {
+	children map[rune]*trie[T]
+	value    T
+	terminal bool // terminal is true when a literal ends at this node.
+}
+
+func newTrie[T any](table map[string]T) *trie[T] {
+	root := &trie[T]{}
+	for word, value := range table {
+		node := root
+		for _, r := range word {
+			child, ok := node.children[r]
+			if !ok {
+				if node.children == nil {
+					node.children = make(map[rune]*trie[T])
+				}
+				child = &trie[T]{}
+				node.children[r] = child
+			}
+			node = child
+		}
+		node.value = value
+		node.terminal = true
+	}
+	return root
+}
+
+// longest walks the trie from pos and returns the node and end index of the
+// longest literal matching the input, and the node where the walk stopped.
+func (t *trie[T]) longest(input []rune, pos int) (match *trie[T], end int, stop *trie[T], stopPos int) {
+	node := t
+	i := pos
+	if node.terminal {
+		match, end = node, i
+	}
+
+	for i < len(input) {
+		child, ok := node.children[input[i]]
+		if !ok {
+			break
+		}
+		node = child
+		i++
+		if node.terminal {
+			match, end = node, i
+		}
+	}
+	return match, end, node, i
+}
+
+// contains reports whether word is exactly one of the literals.
+func (t *trie[T]) contains(word string) bool {
+	node := t
+	for _, r := range word {
+		child, ok := node.children[r]
+		if !ok {
+			return false
+		}
+		node = child
+	}
+	return node.terminal
+}
+
+// words appends every literal below t, each prefixed with prefix, in sorted order.
+func (t *trie[T]) words(prefix []rune, out []string) []string {
+	if t.terminal {
+		out = append(out, string(prefix))
+	}
+
+	keys := make([]rune, 0, len(t.children))
+	for r := range t.children {
+		keys = append(keys, r)
+	}
+	slices.Sort(keys)
+	for _, r := range keys {
+		out = t.children[r].words(append(prefix, r), out)
+	}
+	return out
+}
+
+// Tokens matches the longest key of table at the current position and returns
+// its value. Keys are compiled into a trie, so the input is scanned once no
+// matter how many keys share a prefix, and ordering does not matter.
+//
+// Example:
+//
+//	type Op int
+//	const (Lt Op = iota; Le; Shl; ShlAssign)
+//	op := Tokens(map[string]Op{"<": Lt, "<=": Le, "<<": Shl, "<<=": ShlAssign})
+//	result := Parse(op, "<<= 1")
+//	// result.Value == ShlAssign
+func Tokens[T any](table map[string]T) Parser[T] {
+	root := newTrie(table)
+	all := root.words(nil, nil)
+	want := quoteList(all)
+
+	return func(state State) Result[T] {
+		match, end, stop, stopPos := root.longest(state.Input, state.Pos)
+
+		// Reaching the end of input mid-walk means longer literals could follow.
+		if stopPos == len(state.Input) && len(stop.children) > 0 {
+			prefix := state.Input[state.Pos:stopPos]
+			for _, word := range stop.words(slices.Clone(prefix), nil) {
+				state.expect(word, false)
+			}
+		}
+
+		if match == nil {
+			return Failure[T](newError(errExpectedOneOf, want, state), state)
+		}
+		return Success(match.value, state.advanceTo(end))
+	}
+}
+
+// OneOfStrings matches the longest of the given literals and returns it.
+// Unlike Choice(String(a), String(b), ...), the result does not depend on the
+// order of the literals, and shared prefixes are scanned only once.
+//
+// Example:
+//
+//	op := OneOfStrings("<", "<=", "<<", "<<=")
+//	result := Parse(op, "<=x")
+//	// result.Value == "<="
+func OneOfStrings(literals ...string) Parser[string] {
+	table := make(map[string]string, len(literals))
+	for _, lit := range literals {
+		table[lit] = lit
+	}
+	return Tokens(table)
+}
+
+// Longest runs every parser from the same position and returns the result that
+// consumed the most input. Ties go to the earliest parser. Fails with the error
+// of the last parser if none succeed.
+//
+// Use [OneOfStrings] or [Tokens] for plain literals; Longest is for alternatives
+// that are not literals, such as a float versus an integer.
+//
+// Example:
+//
+//	number := Longest(Map(Integer(), func(n int64) any { return n }), Map(Float(), func(f float64) any { return f }))
+//	result := Parse(number, "3.5")
+//	// result.Value == 3.5
+func Longest[T, I any](parsers ...GParser[T, I]) GParser[T, I] {
+	return func(state GState[I]) GResult[T, I] {
+		var best GResult[T, I]
+		var lastErr error
+		bestIdx := 0
+
+		for i, p := range parsers {
+			r := p(state)
+			if state.aborted() {
+				return r
+			}
+			if !r.OK {
+				lastErr = r.Err
+				continue
+			}
+			if !best.OK || r.State.Pos > best.State.Pos {
+				best, bestIdx = r, i
+			}
+		}
+
+		if best.OK {
+			// Later alternatives overwrote the events of the best one; replay it.
+			if state.emitting() && bestIdx != len(parsers)-1 {
+				return parsers[bestIdx](state)
+			}
+			return best
+		}
+		if lastErr != nil {
+			return Failure[T](lastErr, state)
+		}
+		return Failure[T](newError(errNoAlternatives, "", state), state)
+	}
+}
+
+// IdentExcept matches an identifier like [Ident] but fails if it is one of the
+// reserved words. Reserved words are only rejected as whole identifiers, so
+// "iffy" is accepted even when "if" is reserved.
+//
+// Example:
+//
+//	name := IdentExcept("if", "else", "return")
+//	Parse(name, "total")  // succeeds
+//	Parse(name, "return") // fails: reserved word
+func IdentExcept(reserved ...string) Parser[string] {
+	table := make(map[string]struct{}, len(reserved))
+	for _, word := range reserved {
+		table[word] = struct{}{}
+	}
+	words := newTrie(table)
+	ident := Ident()
+
+	return func(state State) Result[string] {
+		r := ident(state)
+		if r.OK && words.contains(r.Value) {
+			return Failure[string](newError(errReserved, r.Value, state), state)
+		}
+		return r
+	}
+}
+
+// quoteList formats literals as 'a', 'b', 'c' for error messages.
+func quoteList(literals []string) string {
+	quoted := make([]string, len(literals))
+	for i, lit := range literals {
+		quoted[i] = "'" + lit + "'"
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,116 @@
+package combinator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//nolint:paralleltest // tests share parser state
+func TestOneOfStrings(t *testing.T) {
+	op := OneOfStrings("<", "<=", "<<", "<<=")
+
+	t.Run("should return longest match regardless of order", func(t *testing.T) {
+		result := Parse(op, "<<= 1")
+		assert.True(t, result.OK)
+		assert.Equal(t, "<<=", result.Value)
+		assert.Equal(t, 3, result.State.Pos)
+	})
+
+	t.Run("should fall back to shorter match", func(t *testing.T) {
+		result := Parse(op, "<<x")
+		assert.True(t, result.OK)
+		assert.Equal(t, "<<", result.Value)
+	})
+
+	t.Run("should match single rune literal", func(t *testing.T) {
+		assert.Equal(t, "<", Parse(op, "< 2").Value)
+	})
+
+	t.Run("should fail listing alternatives", func(t *testing.T) {
+		result := Parse(op, ">")
+		assert.False(t, result.OK)
+		assert.Contains(t, result.Err.Error(), "expected one of '<', '<<', '<<=', '<='")
+	})
+
+	t.Run("should report longer literals at EOF", func(t *testing.T) {
+		assert.Equal(t, []string{"<<", "<<="}, Completions(op, "<<"))
+	})
+
+	t.Run("should handle unicode literals", func(t *testing.T) {
+		arrows := OneOfStrings("→", "⇒", "→→")
+		assert.Equal(t, "→→", Parse(arrows, "→→x").Value)
+	})
+}
+
+//nolint:paralleltest // tests share parser state
+func TestTokens(t *testing.T) {
+	type op int
+	const (
+		add op = iota + 1
+		inc
+		addAssign
+	)
+	table := Tokens(map[string]op{"+": add, "++": inc, "+=": addAssign})
+
+	t.Run("should return mapped value", func(t *testing.T) {
+		assert.Equal(t, inc, Parse(table, "++x").Value)
+		assert.Equal(t, addAssign, Parse(table, "+= 1").Value)
+		assert.Equal(t, add, Parse(table, "+1").Value)
+	})
+
+	t.Run("should work with ChainL1", func(t *testing.T) {
+		ops := Tokens(map[string]func(int64, int64) int64{
+			"+": func(a, b int64) int64 { return a + b },
+			"-": func(a, b int64) int64 { return a - b },
+		})
+		result := Parse(ChainL1(Integer(), ops), "10-3+1")
+		require.True(t, result.OK)
+		assert.Equal(t, int64(8), result.Value)
+	})
+
+	t.Run("should fail on empty table", func(t *testing.T) {
+		assert.False(t, Parse(Tokens(map[string]int{}), "x").OK)
+	})
+}
+
+//nolint:paralleltest // tests share parser state
+func TestLongest(t *testing.T) {
+	number := Longest(
+		Map(Integer(), func(n int64) any { return n }),
+		Map(Float(), func(f float64) any { return f }),
+	)
+
+	t.Run("should pick the alternative consuming most input", func(t *testing.T) {
+		assert.Equal(t, 3.5, Parse(number, "3.5").Value)
+	})
+
+	t.Run("should prefer earliest on ties", func(t *testing.T) {
+		assert.Equal(t, int64(3), Parse(number, "3").Value)
+	})
+
+	t.Run("should fail when no alternative matches", func(t *testing.T) {
+		assert.False(t, Parse(number, "x").OK)
+	})
+}
+
+//nolint:paralleltest // tests share parser state
+func TestIdentExcept(t *testing.T) {
+	name := IdentExcept("if", "else", "return")
+
+	t.Run("should accept ordinary identifiers", func(t *testing.T) {
+		assert.Equal(t, "total", Parse(name, "total").Value)
+	})
+
+	t.Run("should accept identifiers starting with reserved word", func(t *testing.T) {
+		assert.Equal(t, "iffy", Parse(name, "iffy").Value)
+	})
+
+	t.Run("should reject reserved words", func(t *testing.T) {
+		result := Parse(name, "return x")
+		assert.False(t, result.OK)
+		assert.Equal(t, 0, result.State.Pos)
+		assert.Contains(t, result.Err.Error(), "unexpected reserved word 'return'")
+	})
+}
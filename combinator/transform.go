@@ -1,7 +1,5 @@
 package combinator
 
-import "fmt"
-
 // Map transforms the result of a parser using the provided function.
 // The function receives the parsed value and returns a new value.
 //
@@ -10,8 +8,8 @@ import "fmt"
 //	upper := Map(Letter(), func(r rune) rune {
 //		return unicode.ToUpper(r)
 //	})
-func Map[T, U any](p Parser[T], fn func(T) U) Parser[U] {
-	return func(state State) Result[U] {
+func Map[T, U, I any](p GParser[T, I], fn func(T) U) GParser[U, I] {
+	return func(state GState[I]) GResult[U, I] {
 		r := p(state)
 		if !r.OK {
 			return Failure[U](r.Err, r.State)
@@ -22,8 +20,8 @@ func Map[T, U any](p Parser[T], fn func(T) U) Parser[U] {
 
 // MapErr transforms the error of a failed parser using the provided function.
 // Useful for adding context to error messages.
-func MapErr[T any](p Parser[T], fn func(error) error) Parser[T] {
-	return func(state State) Result[T] {
+func MapErr[T, I any](p GParser[T, I], fn func(error) error) GParser[T, I] {
+	return func(state GState[I]) GResult[T, I] {
 		r := p(state)
 		if r.OK {
 			return r
@@ -34,32 +32,88 @@ func MapErr[T any](p Parser[T], fn func(error) error) Parser[T] {
 
 // Label adds a descriptive name to a parser's error message.
 // Wraps the original error with "expected <label>: <original error>".
+// When the parser fails at the end of input without consuming anything,
+// [Expected] reports the label instead of the parser's own expectations.
 //
 // Example:
 //
 //	digit := Label(Range('0', '9'), "digit")
-func Label[T any](p Parser[T], label string) Parser[T] {
-	return MapErr(p, func(err error) error {
-		return fmt.Errorf("expected %s: %w", label, err)
-	})
+func Label[T, I any](p GParser[T, I], label string) GParser[T, I] {
+	return func(state GState[I]) GResult[T, I] {
+		mark := state.mark()
+		r := p(state)
+		if r.OK {
+			return r
+		}
+		state.relabel(mark, label)
+		if r.Err == errDiscarded {
+			return r
+		}
+		return Failure[T](&labelError{label: label, err: r.Err}, r.State)
+	}
+}
+
+// Recognize runs a parser and returns the input text it consumed, discarding its value.
+// Useful for building tokens from structured parsers without reassembling their parts.
+//
+// Example:
+//
+//	number := Recognize(Seq2(TakeWhile1(unicode.IsDigit), Opt(Char('%'))))
+//	result := Parse(number, "42% off")
+//	// result.Value == "42%"
+func Recognize[T any](p Parser[T]) Parser[string] {
+	return func(state State) Result[string] {
+		r := p(state)
+		if !r.OK {
+			return Failure[string](r.Err, r.State)
+		}
+		return Success(state.text(r.State), r.State)
+	}
 }
 
 // Skip runs a parser but discards its result, returning struct{}.
 // The parser must still succeed.
-func Skip[T any](p Parser[T]) Parser[struct{}] {
+func Skip[T, I any](p GParser[T, I]) GParser[struct{}, I] {
 	return Map(p, func(_ T) struct{} { return struct{}{} })
 }
 
 // SkipMany matches zero or more occurrences, discarding all results.
 // Always succeeds, returning struct{}.
-func SkipMany[T any](p Parser[T]) Parser[struct{}] {
-	return Skip(Many(p))
+// Results are never collected, so combined with [Commit] and [EmitValue] it
+// streams arbitrarily long inputs in constant memory.
+func SkipMany[T, I any](p GParser[T, I]) GParser[struct{}, I] {
+	return func(state GState[I]) GResult[struct{}, I] {
+		current := state
+
+		for {
+			if err := current.checkpoint(); err != nil {
+				return Failure[struct{}](err, current)
+			}
+
+			r := p(current)
+			// Stop at failure, or if the parser doesn't consume input
+			if !r.OK || r.State.Pos == current.Pos {
+				break
+			}
+			current = r.State
+		}
+
+		return Success(struct{}{}, current)
+	}
 }
 
 // SkipMany1 matches one or more occurrences, discarding all results.
 // Fails if no matches are found.
-func SkipMany1[T any](p Parser[T]) Parser[struct{}] {
-	return Skip(Many1(p))
+func SkipMany1[T, I any](p GParser[T, I]) GParser[struct{}, I] {
+	rest := SkipMany(p)
+
+	return func(state GState[I]) GResult[struct{}, I] {
+		r := p(state)
+		if !r.OK {
+			return Failure[struct{}](r.Err, r.State)
+		}
+		return rest(r.State)
+	}
 }
 
 // Not inverts a parser's result: success becomes failure and vice versa.
@@ -70,11 +124,11 @@ func SkipMany1[T any](p Parser[T]) Parser[struct{}] {
 //
 //	// Match identifier that is not a keyword
 //	notKeyword := And(Not(String("if")), Ident())
-func Not[T any](p Parser[T]) Parser[struct{}] {
-	return func(state State) Result[struct{}] {
+func Not[T, I any](p GParser[T, I]) GParser[struct{}, I] {
+	return func(state GState[I]) GResult[struct{}, I] {
 		r := p(state)
 		if r.OK {
-			return Failure[struct{}](fmt.Errorf("unexpected match at line %d, col %d", state.Line, state.Col), state)
+			return Failure[struct{}](newError(errUnexpectedMatch, "", state), state)
 		}
 		return Success(struct{}{}, state)
 	}
@@ -83,8 +137,8 @@ func Not[T any](p Parser[T]) Parser[struct{}] {
 // LookAhead tests a parser without consuming any input.
 // Returns the matched value but leaves the position unchanged.
 // Useful for conditional parsing based on what comes next.
-func LookAhead[T any](p Parser[T]) Parser[T] {
-	return func(state State) Result[T] {
+func LookAhead[T, I any](p GParser[T, I]) GParser[T, I] {
+	return func(state GState[I]) GResult[T, I] {
 		r := p(state)
 		if r.OK {
 			return Success(r.Value, state)
@@ -95,14 +149,21 @@ func LookAhead[T any](p Parser[T]) Parser[T] {
 
 // Lazy defers parser evaluation by accepting a function that returns a parser.
 // Enables mutual recursion between parsers.
-func Lazy[T any](f func() Parser[T]) Parser[T] {
-	return func(state State) Result[T] {
-		return f()(state)
+// Each invocation counts toward the limits set by [WithMaxSteps] and [WithMaxDepth].
+func Lazy[T, I any](f func() GParser[T, I]) GParser[T, I] {
+	return func(state GState[I]) GResult[T, I] {
+		if err := state.enter(); err != nil {
+			return Failure[T](err, state)
+		}
+		r := f()(state)
+		state.leave()
+		return r
 	}
 }
 
 // Ref creates a parser from a Rule pointer, enabling recursive grammar definitions.
 // The Rule is evaluated each time the parser runs.
+// Each invocation counts toward the limits set by [WithMaxSteps] and [WithMaxDepth].
 //
 // Example:
 //
@@ -111,8 +172,13 @@ func Lazy[T any](f func() Parser[T]) Parser[T] {
 //		return Choice(Integer(), Parens(Ref(&expr)))
 //	}
 //	result := Parse(Ref(&expr), "((42))")
-func Ref[T any](r *Rule[T]) Parser[T] {
-	return func(state State) Result[T] {
-		return (*r)()(state)
+func Ref[T, I any](r *GRule[T, I]) GParser[T, I] {
+	return func(state GState[I]) GResult[T, I] {
+		if err := state.enter(); err != nil {
+			return Failure[T](err, state)
+		}
+		res := (*r)()(state)
+		state.leave()
+		return res
 	}
 }
@@ -2,8 +2,10 @@ package combinator
 
 import (
 	"testing"
+	"unicode"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 //nolint:paralleltest // tests share parser state
@@ -60,6 +62,40 @@ func TestLabel(t *testing.T) {
 	})
 }
 
+//nolint:paralleltest // tests share parser state
+func TestRecognize(t *testing.T) {
+	t.Run("should return consumed text", func(t *testing.T) {
+		parser := Recognize(Seq3(Char('v'), Integer(), Opt(String("-rc"))))
+		result := Parse(parser, "v12-rc rest")
+		assert.True(t, result.OK)
+		assert.Equal(t, "v12-rc", result.Value)
+		assert.Equal(t, 6, result.State.Pos)
+	})
+
+	t.Run("should return text across lines", func(t *testing.T) {
+		parser := Recognize(SepBy1(Ident(), Newline()))
+		result := Parse(parser, "a\nb\nc")
+		assert.Equal(t, "a\nb\nc", result.Value)
+		assert.Equal(t, 3, result.State.Line)
+	})
+
+	t.Run("should propagate failure", func(t *testing.T) {
+		result := Parse(Recognize(Seq2(Char('a'), Char('b'))), "ax")
+		assert.False(t, result.OK)
+		assert.Contains(t, result.Err.Error(), "expected 'b'")
+	})
+
+	t.Run("should work on manually built state", func(t *testing.T) {
+		state := State{Input: []rune("héllo\nwörld"), Line: 1, Col: 1}
+		result := Recognize(SepBy(TakeWhile1(unicode.IsLetter), Newline()))(state)
+		require.True(t, result.OK)
+		assert.Equal(t, "héllo\nwörld", result.Value)
+		assert.Equal(t, 2, result.State.Line)
+		assert.Equal(t, 6, result.State.Col)
+		assert.Equal(t, 13, result.State.Position().Offset)
+	})
+}
+
 //nolint:paralleltest // tests share parser state
 func TestSkip(t *testing.T) {
 	t.Run("should return empty struct on success", func(t *testing.T) {
@@ -45,6 +45,18 @@
 //		return v * 2
 //	})
 //
+// # Other Inputs
+//
+// Parsers are generic over their input elements: a [GParser] of element type I
+// runs on a [GState] of I, and [Parser] and [State] are the versions for text.
+// The combinators, such as [Seq2], [Choice], [Many] and [Map], accept parsers
+// of any element type, so they also parse bytes, tokens produced by a separate
+// lexer, or the lines of a file. Use [Item], [ItemIf] and [EndOfInput] as the
+// primitives and [ParseInput] to run them:
+//
+//	num := combinator.ItemIf(func(t Token) bool { return t.Kind == "num" })
+//	result := combinator.ParseInput(combinator.Many(num), tokens)
+//
 // # Recursive Grammars
 //
 // Use [Rule] and [Ref] for recursive definitions:
@@ -58,15 +70,54 @@
 //	}
 package combinator
 
-// State represents the current position and context within the input being parsed.
-// Tracks line and column numbers for meaningful error messages.
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// GState represents the current position and context within an input of
+// elements of type I. Tracks line and column numbers for meaningful error messages.
+//
+// Create a new GState with [NewInputState] rather than constructing directly.
+type GState[I any] struct {
+	Input []I // Input stores the complete input.
+	Pos   int // Pos is the index of the current element in Input.
+	Line  int // Line is the current line number (1-indexed), advanced by '\n' runes and bytes.
+	Col   int // Col is the current column number (1-indexed), counting elements.
+
+	src     string   // src is text Input as a string, sliced to return matched text without allocating; empty for other elements.
+	off     int      // off is the byte offset of Pos within src.
+	items   bool     // items marks input that is not text, whose elements never end a line.
+	events  int      // events counts the events emitted on the path to this State.
+	source  *source  // source names the input for positions and errors; nil if anonymous.
+	session *session // session is shared by every State derived from the same parse.
+}
+
+// State is the state of a parser over text, whose elements are runes.
 //
 // Create a new State with [NewState] rather than constructing directly.
-type State struct {
-	Input []rune // Input stores the complete input as runes for Unicode support.
-	Pos   int    // Pos is the current byte position in Input.
-	Line  int    // Line is the current line number (1-indexed).
-	Col   int    // Col is the current column number (1-indexed).
+type State = GState[rune]
+
+// session holds bookkeeping shared across a single parse run.
+// A nil session disables all optional tracking and limits.
+type session struct {
+	quiet    bool          // quiet replaces failure errors with errDiscarded when only success matters.
+	tracking bool          // tracking enables recording of expectations at EOF.
+	expected []Expectation // expected collects what could follow the end of input.
+
+	ctx      context.Context // ctx cancels the parse when done; nil means never.
+	maxSteps int             // maxSteps bounds rule invocations; zero means unlimited.
+	maxDepth int             // maxDepth bounds rule nesting; zero means unlimited.
+	steps    int             // steps counts rule invocations so far.
+	depth    int             // depth is the current rule nesting level.
+	err      error           // err is set once a limit trips or an include fails, aborting the parse.
+	polls    int             // polls counts the loop iterations passed through checkpoint.
+
+	sink    func(Event) bool // sink receives committed events; nil disables events.
+	pending []Event          // pending holds emitted events not yet delivered to sink.
+	flushed int              // flushed counts the events already delivered to sink.
 }
 
 // NewState creates a parser state initialized at the beginning of the input string.
@@ -77,43 +128,85 @@ type State struct {
 //	state := NewState("hello")
 //	fmt.Println(state.Current()) // 'h'
 func NewState(input string) State {
+	runes := []rune(input)
+	if !utf8.ValidString(input) {
+		// Keep src in sync with Input, where invalid bytes became U+FFFD.
+		input = string(runes)
+	}
+
 	return State{
-		Input: []rune(input),
+		Input: runes,
 		Pos:   0,
 		Line:  1,
 		Col:   1,
+		src:   input,
 	}
 }
 
-// Current returns the rune at the current position, or 0 if at end of input.
-func (s State) Current() rune {
+// NewInputState creates a parser state initialized at the beginning of input,
+// for parsers over elements other than the runes of a string, such as bytes,
+// tokens produced by a separate lexer, or the lines of a file.
+//
+// Example:
+//
+//	state := NewInputState([]Token{{Kind: "ident", Text: "x"}})
+//	fmt.Println(state.Current().Text) // "x"
+func NewInputState[I any](input []I) GState[I] {
+	state := GState[I]{
+		Input: input,
+		Pos:   0,
+		Line:  1,
+		Col:   1,
+	}
+
+	// Text is tracked through src, so lines are counted and matches sliced
+	// without inspecting the element type on every step.
+	switch in := any(input).(type) {
+	case []rune:
+		state.src = string(in)
+	case []byte:
+		state.src = string(in)
+	default:
+		state.items = true
+	}
+	return state
+}
+
+// Current returns the element at the current position, or the zero value if at end of input.
+func (s GState[I]) Current() I {
 	if s.Pos >= len(s.Input) {
-		return 0
+		var zero I
+		return zero
 	}
 	return s.Input[s.Pos]
 }
 
 // IsEOF reports whether the parser has reached the end of input.
-func (s State) IsEOF() bool {
+func (s GState[I]) IsEOF() bool {
 	return s.Pos >= len(s.Input)
 }
 
-// Advance returns a new State moved forward by one rune.
+// Advance returns a new State moved forward by one element.
 // Updates line and column tracking when encountering newlines.
 // Returns the same state unchanged if already at EOF.
-func (s State) Advance() State {
+func (s GState[I]) Advance() GState[I] {
 	if s.IsEOF() {
 		return s
 	}
 
-	next := State{
-		Input: s.Input,
-		Pos:   s.Pos + 1,
-		Line:  s.Line,
-		Col:   s.Col + 1,
+	next := s
+	next.Pos++
+	next.Col++
+
+	width, newline := 1, false
+	if s.src != "" {
+		width, newline = s.width(1), s.src[s.off] == '\n'
+	} else if !s.items {
+		width, newline = s.measure(s.Pos)
 	}
 
-	if s.Current() == '\n' {
+	next.off += width
+	if newline {
 		next.Line++
 		next.Col = 1
 	}
@@ -121,35 +214,143 @@ func (s State) Advance() State {
 	return next
 }
 
-// AdvanceN returns a new State moved forward by n runes.
-// Equivalent to calling Advance n times.
-func (s State) AdvanceN(n int) State {
-	current := s
+// AdvanceN returns a new State moved forward by n elements.
+// Equivalent to calling Advance n times, but updates line and column tracking
+// in a single pass. Stops at the end of input.
+func (s GState[I]) AdvanceN(n int) GState[I] {
+	if n <= 0 {
+		return s
+	}
+	return s.advanceTo(min(s.Pos+n, len(s.Input)))
+}
+
+// advanceTo returns a new State moved forward to the element index end.
+// The caller guarantees Pos <= end <= len(Input).
+func (s GState[I]) advanceTo(end int) GState[I] {
+	n := end - s.Pos
+	next := s
+	next.Pos = end
+	next.Col += n
+
+	if s.src == "" {
+		if s.items {
+			next.off += n
+			return next
+		}
+		next.Col = s.Col
+		for i := s.Pos; i < end; i++ {
+			width, newline := s.measure(i)
+			next.off += width
+			next.Col++
+			if newline {
+				next.Line++
+				next.Col = 1
+			}
+		}
+		return next
+	}
+
+	width := s.width(n)
+	next.off += width
+
+	text := s.src[s.off:next.off]
+	if lines := strings.Count(text, "\n"); lines > 0 {
+		last := text[strings.LastIndexByte(text, '\n')+1:]
+		next.Line += lines
+		next.Col = 1 + len(last)
+		if width != n {
+			next.Col = 1 + utf8.RuneCountInString(last)
+		}
+	}
+	return next
+}
+
+// width returns the number of bytes of src taken by the n elements at Pos.
+// Text is measured through src rather than Input so that every element type
+// shares one implementation: when src has a byte per element, as for bytes
+// or ASCII text, each element is one byte wide; otherwise elements are runes.
+func (s GState[I]) width(n int) int {
+	if len(s.src) == len(s.Input) {
+		return n
+	}
+
+	width := 0
 	for range n {
-		current = current.Advance()
+		_, size := utf8.DecodeRuneInString(s.src[s.off+width:])
+		width += size
+	}
+	return width
+}
+
+// measure returns the UTF-8 width of the element at i and whether it ends a line.
+// It serves text states built without src, such as a [State] literal; states
+// from [NewState] and [NewInputState] never inspect the element type.
+func (s GState[I]) measure(i int) (width int, newline bool) {
+	switch e := any(s.Input[i]).(type) {
+	case rune:
+		if width = utf8.RuneLen(e); width < 0 {
+			width = utf8.RuneLen(utf8.RuneError)
+		}
+		return width, e == '\n'
+	case byte:
+		return 1, e == '\n'
 	}
-	return current
+	return 1, false
 }
 
-// Result holds the outcome of applying a parser to input.
+// scan returns the index of the first element at or after from that does not satisfy pred.
+func (s GState[I]) scan(from int, pred func(I) bool) int {
+	end := from
+	for end < len(s.Input) && pred(s.Input[end]) {
+		end++
+	}
+	return end
+}
+
+// text returns the input between s and a later state end.
+// Slices the original string when available instead of allocating.
+func (s GState[I]) text(end GState[I]) string {
+	if s.src != "" {
+		return s.src[s.off:end.off]
+	}
+	switch in := any(s.Input).(type) {
+	case []rune:
+		return string(in[s.Pos:end.Pos])
+	case []byte:
+		return string(in[s.Pos:end.Pos])
+	}
+	return fmt.Sprint(s.Input[s.Pos:end.Pos])
+}
+
+// GResult holds the outcome of applying a parser to an input of elements of type I.
 // Check OK to determine success before accessing Value or Err.
-type Result[T any] struct {
-	OK    bool  // OK is true when parsing succeeded.
-	Value T     // Value contains the parsed result on success.
-	State State // State is the parser position after this result.
-	Err   error // Err contains the error message on failure.
+type GResult[T, I any] struct {
+	OK    bool      // OK is true when parsing succeeded.
+	Value T         // Value contains the parsed result on success.
+	State GState[I] // State is the parser position after this result.
+	Err   error     // Err contains the error message on failure.
 }
 
-// Parser is a function that consumes input and produces a result.
-// All parsing primitives and combinators in this package are Parsers.
+// Result holds the outcome of applying a parser to text.
+type Result[T any] = GResult[T, rune]
+
+// GParser is a function that consumes an input of elements of type I and
+// produces a result. The combinators of this package, such as [Seq2], [Choice]
+// and [Many], work with any element type; the character parsers work on text.
 //
-// A Parser receives the current State, attempts to match, and returns a Result.
+// A GParser receives the current State, attempts to match, and returns a Result.
 // On success, the Result contains the matched value and advanced State.
 // On failure, the Result contains an error and the original State.
-type Parser[T any] func(State) Result[T]
+type GParser[T, I any] func(GState[I]) GResult[T, I]
 
-// Rule enables recursive grammar definitions by deferring parser construction.
+// Parser is a parser over text. All character parsers in this package are Parsers.
+type Parser[T any] = GParser[T, rune]
+
+// GRule enables recursive grammar definitions by deferring parser construction.
 // Use with [Ref] to create self-referential parsers.
+type GRule[T, I any] func() GParser[T, I]
+
+// Rule is a [GRule] over text.
 //
 // Example:
 //
@@ -157,12 +358,12 @@ type Parser[T any] func(State) Result[T]
 //	expr = func() Parser[int64] {
 //		return Choice(Integer(), Parens(Ref(&expr)))
 //	}
-type Rule[T any] func() Parser[T]
+type Rule[T any] = GRule[T, rune]
 
 // Success constructs a successful parse result with the given value and updated state.
 // Used internally by parsers; most users should use the higher-level combinators.
-func Success[T any](value T, state State) Result[T] {
-	return Result[T]{
+func Success[T, I any](value T, state GState[I]) GResult[T, I] {
+	return GResult[T, I]{
 		OK:    true,
 		Value: value,
 		State: state,
@@ -171,8 +372,8 @@ func Success[T any](value T, state State) Result[T] {
 
 // Failure constructs a failed parse result with the given error and state.
 // Used internally by parsers; most users should use the higher-level combinators.
-func Failure[T any](err error, state State) Result[T] {
-	return Result[T]{
+func Failure[T, I any](err error, state GState[I]) GResult[T, I] {
+	return GResult[T, I]{
 		OK:    false,
 		Err:   err,
 		State: state,
@@ -182,14 +383,53 @@ func Failure[T any](err error, state State) Result[T] {
 // Parse runs a parser on the input string and returns the result.
 // This is the main entry point for using parsers.
 //
+// Options such as [WithContext], [WithMaxSteps] and [WithMaxDepth] bound the
+// work done on untrusted input. When a limit trips the parse is aborted and the
+// result fails with [ErrCanceled], [ErrStepLimit] or [ErrDepthLimit].
+// [WithEvents] streams the events of [Emit] and [EmitValue] parsers.
+//
 // Example:
 //
 //	result := Parse(Integer(), "42")
 //	if result.OK {
 //		fmt.Println(result.Value) // 42
 //	}
-func Parse[T any](p Parser[T], input string) Result[T] {
-	return p(NewState(input))
+func Parse[T any](p Parser[T], input string, opts ...Option) Result[T] {
+	return run(p, NewState(input), opts)
+}
+
+// ParseInput runs a parser on a slice of input elements, such as bytes or
+// tokens, and returns the result. Options work as with [Parse].
+//
+// Example:
+//
+//	tokens := lex("x = 1")
+//	result := ParseInput(assignment, tokens)
+func ParseInput[T, I any](p GParser[T, I], input []I, opts ...Option) GResult[T, I] {
+	return run(p, NewInputState(input), opts)
+}
+
+// run applies the options to a fresh session for state and runs p.
+// Aborts recorded in the session, by limits or by [Include], fail the result.
+// Parses without options of anonymous input need no session and skip it.
+func run[T, I any](p GParser[T, I], state GState[I], opts []Option) GResult[T, I] {
+	if len(opts) == 0 && state.source == nil {
+		return p(state)
+	}
+
+	state.session = &session{}
+	for _, opt := range opts {
+		opt(state.session)
+	}
+
+	r := p(state)
+	if r.OK && r.State.emitting() {
+		r.State.flush()
+	}
+	if state.session.err != nil {
+		return Failure[T](state.session.err, r.State)
+	}
+	return r
 }
 
 // Run executes a parser, updates the state on success, and returns the typed value.
@@ -203,7 +443,7 @@ func Parse[T any](p Parser[T], input string) Result[T] {
 //		return Failure[MyType](err, state)
 //	}
 //	// state is now advanced past the identifier
-func Run[T any](p Parser[T], s *State) (T, error) {
+func Run[T, I any](p GParser[T, I], s *GState[I]) (T, error) {
 	var zero T
 	res := p(*s)
 	if !res.OK {
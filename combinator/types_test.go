@@ -106,6 +106,21 @@ func TestState_AdvanceN(t *testing.T) {
 		assert.Equal(t, 2, next.Line)
 		assert.Equal(t, 2, next.Col)
 	})
+
+	t.Run("should count columns in runes", func(t *testing.T) {
+		next := NewState("é\nçã!").AdvanceN(4)
+		assert.Equal(t, 2, next.Line)
+		assert.Equal(t, 3, next.Col)
+		assert.Equal(t, '!', next.Current())
+		assert.Equal(t, 7, next.Position().Offset)
+	})
+
+	t.Run("should count columns in bytes", func(t *testing.T) {
+		next := NewInputState([]byte("é\nçã!")).AdvanceN(6)
+		assert.Equal(t, 2, next.Line)
+		assert.Equal(t, 4, next.Col)
+		assert.Equal(t, byte(0xa3), next.Current())
+	})
 }
 
 //nolint:paralleltest // tests share parser state
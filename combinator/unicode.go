@@ -0,0 +1,248 @@
+package combinator
+
+import "unicode"
+
+// StringFold matches a string regardless of case, using Unicode simple case folding.
+// Returns the text as it appears in the input.
+//
+// Example:
+//
+//	result := Parse(StringFold("select"), "SeLeCt *")
+//	// result.Value == "SeLeCt"
+func StringFold(s string) Parser[string] {
+	runes := []rune(s)
+
+	return func(state State) Result[string] {
+		rest := state.Input[state.Pos:]
+
+		for i, r := range runes {
+			if i >= len(rest) {
+				state.expect(s, false)
+				return Failure[string](newError(errExpectedAtEOF, s, state), state)
+			}
+
+			if !equalFold(rest[i], r) {
+				return Failure[string](newError(errExpected, s, state), state)
+			}
+		}
+
+		next := state.advanceTo(state.Pos + len(runes))
+		return Success(state.text(next), next)
+	}
+}
+
+// KeywordFold matches a keyword regardless of case that is not followed by
+// alphanumeric characters. The case-insensitive counterpart of [Keyword].
+// Returns the text as it appears in the input.
+//
+// Example:
+//
+//	result := Parse(KeywordFold("from"), "FROM users") // succeeds
+//	result = Parse(KeywordFold("from"), "fromage")     // fails
+func KeywordFold(kw string) Parser[string] {
+	word := StringFold(kw)
+
+	return func(state State) Result[string] {
+		r := word(state)
+		if r.OK && !r.State.IsEOF() && isAlphaNum(r.State.Current()) {
+			return Failure[string](newError(errUnexpectedMatch, "", r.State), r.State)
+		}
+		return r
+	}
+}
+
+// equalFold reports whether a and b are equal under simple case folding.
+func equalFold(a, b rune) bool {
+	if a == b {
+		return true
+	}
+	for f := unicode.SimpleFold(a); f != a; f = unicode.SimpleFold(f) {
+		if f == b {
+			return true
+		}
+	}
+	return false
+}
+
+// UnicodeClass matches a single character belonging to any of the given Unicode
+// tables, such as categories (unicode.Lu), scripts (unicode.Han) or properties
+// (unicode.White_Space). Returns the matched rune.
+//
+// Example:
+//
+//	han := Many1(UnicodeClass(unicode.Han))
+//	result := Parse(han, "漢字かな")
+//	// result.Value == []rune{'漢', '字'}
+func UnicodeClass(tables ...*unicode.RangeTable) Parser[rune] {
+	return Satisfy(func(r rune) bool {
+		return unicode.IsOneOf(tables, r)
+	})
+}
+
+// IsIDStart reports whether r may start an identifier per Unicode Standard Annex #31
+// (the ID_Start property: letters, letter numbers and Other_ID_Start, excluding
+// pattern syntax and pattern whitespace).
+func IsIDStart(r rune) bool {
+	if unicode.Is(unicode.Pattern_Syntax, r) || unicode.Is(unicode.Pattern_White_Space, r) {
+		return false
+	}
+	return unicode.IsLetter(r) || unicode.Is(unicode.Nl, r) || unicode.Is(unicode.Other_ID_Start, r)
+}
+
+// IsIDContinue reports whether r may continue an identifier per Unicode Standard
+// Annex #31 (the ID_Continue property: ID_Start plus combining marks, decimal
+// digits, connector punctuation and Other_ID_Continue).
+func IsIDContinue(r rune) bool {
+	if IsIDStart(r) {
+		return true
+	}
+	if unicode.Is(unicode.Pattern_Syntax, r) || unicode.Is(unicode.Pattern_White_Space, r) {
+		return false
+	}
+	return unicode.In(r, unicode.Mn, unicode.Mc, unicode.Nd, unicode.Pc, unicode.Other_ID_Continue)
+}
+
+// UnicodeIdent matches an identifier using the default identifier syntax of
+// Unicode Standard Annex #31: an ID_Start character followed by ID_Continue
+// characters. Unlike [Ident], accepts combining marks (e.g. "naïve") and
+// rejects a leading underscore, which is connector punctuation.
+//
+// Example:
+//
+//	result := Parse(UnicodeIdent(), "größe = 3")
+//	// result.Value == "größe"
+func UnicodeIdent() Parser[string] {
+	return Recognize(Seq2(Label(Satisfy(IsIDStart), "identifier"), TakeWhile(IsIDContinue)))
+}
+
+// AnyGrapheme matches a single user-perceived character (extended grapheme cluster)
+// and returns its text. Keeps base characters together with combining marks,
+// emoji modifier and ZWJ sequences, flag pairs, Hangul syllable sequences and CRLF.
+//
+// Segmentation follows the rules of Unicode Standard Annex #29 using the
+// properties available in the standard library, with Extended_Pictographic
+// approximated by the symbol categories.
+//
+// Example:
+//
+//	result := Parse(Many(AnyGrapheme()), "é👍🏽🇧🇷")
+//	// result.Value == []string{"é", "👍🏽", "🇧🇷"}
+func AnyGrapheme() Parser[string] {
+	return func(state State) Result[string] {
+		if state.IsEOF() {
+			return Failure[string](newError(errUnexpectedEOF, "", state), state)
+		}
+
+		next := state.advanceTo(graphemeEnd(state.Input, state.Pos))
+		return Success(state.text(next), next)
+	}
+}
+
+// graphemeEnd returns the index just past the grapheme cluster starting at pos.
+func graphemeEnd(input []rune, pos int) int {
+	prev := input[pos]
+	end := pos + 1
+	riCount := 0
+	if isRegionalIndicator(prev) {
+		riCount = 1
+	}
+	afterPictographic := isPictographic(prev)
+
+	for end < len(input) {
+		r := input[end]
+		if !graphemeContinues(prev, r, afterPictographic, riCount) {
+			break
+		}
+
+		switch {
+		case isRegionalIndicator(r):
+			riCount++
+		case isPictographic(r):
+			afterPictographic = true
+		case !isGraphemeExtend(r) && r != zwj:
+			afterPictographic = false
+		}
+		prev = r
+		end++
+	}
+
+	return end
+}
+
+const zwj = '\u200d'
+
+// graphemeContinues reports whether there is no grapheme break between prev and r.
+func graphemeContinues(prev, r rune, afterPictographic bool, riCount int) bool {
+	switch {
+	case prev == '\r' && r == '\n': // GB3
+		return true
+	case isControl(prev) || isControl(r): // GB4, GB5
+		return false
+	case hangulContinues(prev, r): // GB6-GB8
+		return true
+	case isGraphemeExtend(r) || r == zwj || unicode.Is(unicode.Mc, r): // GB9, GB9a
+		return true
+	case prev == zwj && afterPictographic && isPictographic(r): // GB11
+		return true
+	case isRegionalIndicator(prev) && isRegionalIndicator(r): // GB12, GB13
+		return riCount%2 == 1
+	}
+	return false
+}
+
+func isControl(r rune) bool {
+	return r == '\r' || r == '\n' || unicode.In(r, unicode.Cc, unicode.Zl, unicode.Zp)
+}
+
+func isGraphemeExtend(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Other_Grapheme_Extend) ||
+		(r >= 0x1F3FB && r <= 0x1F3FF) // emoji modifiers
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+// isPictographic approximates the Extended_Pictographic property.
+func isPictographic(r rune) bool {
+	return unicode.Is(unicode.So, r) || (r >= 0x1F000 && r <= 0x1FAFF && !isRegionalIndicator(r) && !isGraphemeExtend(r))
+}
+
+// Hangul jamo classes used by the syllable sequence rules.
+const (
+	hangulL = iota + 1
+	hangulV
+	hangulT
+	hangulLV
+	hangulLVT
+)
+
+func hangulType(r rune) int {
+	switch {
+	case (r >= 0x1100 && r <= 0x115F) || (r >= 0xA960 && r <= 0xA97C):
+		return hangulL
+	case (r >= 0x1160 && r <= 0x11A7) || (r >= 0xD7B0 && r <= 0xD7C6):
+		return hangulV
+	case (r >= 0x11A8 && r <= 0x11FF) || (r >= 0xD7CB && r <= 0xD7FB):
+		return hangulT
+	case r >= 0xAC00 && r <= 0xD7A3:
+		if (r-0xAC00)%28 == 0 {
+			return hangulLV
+		}
+		return hangulLVT
+	}
+	return 0
+}
+
+func hangulContinues(prev, r rune) bool {
+	p, n := hangulType(prev), hangulType(r)
+	switch p {
+	case hangulL:
+		return n == hangulL || n == hangulV || n == hangulLV || n == hangulLVT
+	case hangulLV, hangulV:
+		return n == hangulV || n == hangulT
+	case hangulLVT, hangulT:
+		return n == hangulT
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package combinator
+
+import (
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//nolint:paralleltest // tests share parser state
+func TestStringFold(t *testing.T) {
+	t.Run("should match regardless of case", func(t *testing.T) {
+		result := Parse(StringFold("select"), "SeLeCt *")
+		assert.True(t, result.OK)
+		assert.Equal(t, "SeLeCt", result.Value)
+		assert.Equal(t, 6, result.State.Pos)
+	})
+
+	t.Run("should use unicode folding", func(t *testing.T) {
+		result := Parse(StringFold("straße"), "STRAßE")
+		assert.True(t, result.OK)
+
+		result = Parse(StringFold("k"), "K") // Kelvin sign folds to k
+		assert.True(t, result.OK)
+	})
+
+	t.Run("should fail on mismatch", func(t *testing.T) {
+		result := Parse(StringFold("select"), "selekt")
+		assert.False(t, result.OK)
+		assert.Contains(t, result.Err.Error(), "expected 'select'")
+	})
+
+	t.Run("should report completion at EOF", func(t *testing.T) {
+		assert.Equal(t, []string{"select"}, Completions(StringFold("select"), "SEL"))
+	})
+}
+
+//nolint:paralleltest // tests share parser state
+func TestKeywordFold(t *testing.T) {
+	t.Run("should match keyword regardless of case", func(t *testing.T) {
+		result := Parse(KeywordFold("from"), "FROM users")
+		assert.True(t, result.OK)
+		assert.Equal(t, "FROM", result.Value)
+	})
+
+	t.Run("should require word boundary", func(t *testing.T) {
+		assert.False(t, Parse(KeywordFold("from"), "Fromage").OK)
+	})
+}
+
+//nolint:paralleltest // tests share parser state
+func TestUnicodeClass(t *testing.T) {
+	t.Run("should match script", func(t *testing.T) {
+		result := Parse(Many1(UnicodeClass(unicode.Han)), "漢字かな")
+		assert.True(t, result.OK)
+		assert.Equal(t, []rune{'漢', '字'}, result.Value)
+	})
+
+	t.Run("should match any of several tables", func(t *testing.T) {
+		kana := UnicodeClass(unicode.Hiragana, unicode.Katakana)
+		assert.True(t, Parse(kana, "カ").OK)
+		assert.True(t, Parse(kana, "か").OK)
+		assert.False(t, Parse(kana, "漢").OK)
+	})
+}
+
+//nolint:paralleltest // tests share parser state
+func TestUnicodeIdent(t *testing.T) {
+	t.Run("should accept letters from any script", func(t *testing.T) {
+		result := Parse(UnicodeIdent(), "größe = 3")
+		assert.True(t, result.OK)
+		assert.Equal(t, "größe", result.Value)
+	})
+
+	t.Run("should accept combining marks after start", func(t *testing.T) {
+		result := Parse(UnicodeIdent(), "nai\u0308ve")
+		assert.Equal(t, "nai\u0308ve", result.Value)
+	})
+
+	t.Run("should accept connector punctuation after start", func(t *testing.T) {
+		assert.Equal(t, "snake_case", Parse(UnicodeIdent(), "snake_case").Value)
+	})
+
+	t.Run("should reject leading digit or underscore", func(t *testing.T) {
+		assert.False(t, Parse(UnicodeIdent(), "1abc").OK)
+		assert.False(t, Parse(UnicodeIdent(), "_abc").OK)
+	})
+
+	t.Run("should classify properties", func(t *testing.T) {
+		assert.True(t, IsIDStart('Ⅻ'))       // letter number
+		assert.False(t, IsIDStart('\u0301')) // combining mark
+		assert.True(t, IsIDContinue('\u0301'))
+		assert.False(t, IsIDContinue('-'))
+	})
+}
+
+//nolint:paralleltest // tests share parser state
+func TestAnyGrapheme(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"ascii", "abc", []string{"a", "b", "c"}},
+		{"combining mark", "e\u0301x", []string{"e\u0301", "x"}},
+		{"crlf", "a\r\nb", []string{"a", "\r\n", "b"}},
+		{"emoji modifier", "👍\U0001F3FD!", []string{"👍\U0001F3FD", "!"}},
+		{"zwj sequence", "👩\u200d💻x", []string{"👩\u200d💻", "x"}},
+		{"flags", "🇧🇷🇯🇵", []string{"🇧🇷", "🇯🇵"}},
+		{"odd regional indicators", "🇧🇷🇯", []string{"🇧🇷", "🇯"}},
+		{"hangul jamo", "\u1100\u1161\u11A8\uAC00", []string{"\u1100\u1161\u11A8", "\uAC00"}},
+		{"variation selector", "☺\ufe0fa", []string{"☺\ufe0f", "a"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := Parse(Many(AnyGrapheme()), tc.input)
+			require.True(t, result.OK)
+			assert.Equal(t, tc.want, result.Value)
+			assert.True(t, result.State.IsEOF())
+		})
+	}
+
+	t.Run("should fail on EOF", func(t *testing.T) {
+		assert.False(t, Parse(AnyGrapheme(), "").OK)
+	})
+}
@@ -0,0 +1,232 @@
+package ghostline
+
+import (
+	"context"
+	"slices"
+	"strings"
+)
+
+// Candidate is a completion offered by a Completer.
+// Accepting it replaces the buffer runes in [Start, End) with Text.
+//
+// Example:
+//
+//	// Buffer "git checkout ma" with the cursor at the end
+//	ghostline.Candidate{Text: "main", Description: "default branch", Start: 13, End: 15}
+type Candidate struct {
+	Text        string // text inserted into the buffer
+	Display     string // label shown in menus instead of Text (optional)
+	Description string // short help shown next to the candidate (optional)
+	Start       int    // first replaced rune index in the buffer
+	End         int    // rune index after the last replaced rune
+}
+
+// label returns the text shown for the candidate in menus.
+func (c Candidate) label() string {
+	if c.Display != "" {
+		return c.Display
+	}
+	return c.Text
+}
+
+// Completer produces completion candidates for the input.
+// Complete receives the whole buffer and the cursor position (a rune index),
+// so it can complete depending on context, e.g. offering flags for the
+// subcommand typed before the cursor. Candidates are shown in the given
+// order; the first one is displayed as ghost text.
+//
+// Complete is called on the goroutine running Readline whenever the buffer
+// changes, so it should return quickly; see AsyncCompleter for slow sources.
+// It must not modify or retain buffer.
+type Completer interface {
+	Complete(buffer []rune, cursor int) []Candidate
+}
+
+// CompleterFunc adapts an ordinary function to the Completer interface.
+//
+// Example:
+//
+//	input.SetCompleter(ghostline.CompleterFunc(func(buffer []rune, cursor int) []ghostline.Candidate {
+//		return completeArgs(string(buffer[:cursor]))
+//	}))
+type CompleterFunc func(buffer []rune, cursor int) []Candidate
+
+// Complete calls f(buffer, cursor).
+func (f CompleterFunc) Complete(buffer []rune, cursor int) []Candidate {
+	return f(buffer, cursor)
+}
+
+// AsyncCompleter is a Completer whose candidates may take a while to compute,
+// such as names of remote resources. Readline shows the candidates returned by
+// Complete immediately (they may be nil) and runs CompleteAsync in the
+// background while the user keeps typing. Its candidates replace the immediate
+// ones when they arrive, as long as the buffer is unchanged.
+//
+// The context is cancelled as soon as the buffer changes or Readline returns,
+// so CompleteAsync should stop and return early when ctx is done.
+// The buffer passed to CompleteAsync is a copy owned by the call.
+type AsyncCompleter interface {
+	Completer
+	CompleteAsync(ctx context.Context, buffer []rune, cursor int) []Candidate
+}
+
+// AsyncCompleterFunc adapts an ordinary function to the AsyncCompleter
+// interface. It offers no immediate candidates.
+//
+// Example:
+//
+//	input.SetCompleter(ghostline.AsyncCompleterFunc(func(ctx context.Context, buffer []rune, cursor int) []ghostline.Candidate {
+//		names, err := client.ListBuckets(ctx)
+//		if err != nil {
+//			return nil
+//		}
+//		return ghostline.StaticCompleter(names).Complete(buffer, cursor)
+//	}))
+type AsyncCompleterFunc func(ctx context.Context, buffer []rune, cursor int) []Candidate
+
+// Complete returns no candidates; results are delivered by CompleteAsync.
+func (f AsyncCompleterFunc) Complete([]rune, int) []Candidate {
+	return nil
+}
+
+// CompleteAsync calls f(ctx, buffer, cursor).
+func (f AsyncCompleterFunc) CompleteAsync(ctx context.Context, buffer []rune, cursor int) []Candidate {
+	return f(ctx, buffer, cursor)
+}
+
+// StaticCompleter completes the last word of the buffer from a fixed list of
+// suggestions. This is the completer used by NewInput.
+// Prefix matches come first, followed by fuzzy matches sorted by score.
+//
+// Example:
+//
+//	StaticCompleter{"commit", "checkout"}.Complete([]rune("git co"), 6)
+//	// returns candidates "commit" and "checkout" replacing runes [4, 6)
+type StaticCompleter []string
+
+// Complete returns the suggestions matching the last word of buffer.
+// The cursor position is ignored; the word at the end of the buffer is completed.
+func (s StaticCompleter) Complete(buffer []rune, _ int) []Candidate {
+	lastWord := extractLastWord(string(buffer))
+	if lastWord == "" {
+		return nil
+	}
+
+	var matches []scoredMatch
+
+	for _, suggestion := range s {
+		isPrefix := strings.HasPrefix(strings.ToLower(suggestion), strings.ToLower(lastWord))
+		score := fuzzyScore(lastWord, suggestion)
+
+		if isPrefix || score >= 0 {
+			matches = append(matches, scoredMatch{
+				text:     suggestion,
+				score:    score,
+				isPrefix: isPrefix,
+			})
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil
+	}
+
+	slices.SortFunc(matches, func(a, b scoredMatch) int {
+		if a.isPrefix != b.isPrefix {
+			if a.isPrefix {
+				return -1
+			}
+			return 1
+		}
+		if b.score != a.score {
+			return b.score - a.score
+		}
+		return strings.Compare(strings.ToLower(a.text), strings.ToLower(b.text))
+	})
+
+	start := lastWordStart(buffer)
+	candidates := make([]Candidate, len(matches))
+	for idx, m := range matches {
+		candidates[idx] = Candidate{Text: m.text, Start: start, End: len(buffer)}
+	}
+	return candidates
+}
+
+// asyncCompletion carries the result of a background CompleteAsync call.
+type asyncCompletion struct {
+	buffer     string // buffer the candidates were computed for
+	cursor     int    // cursor position the candidates were computed for
+	candidates []Candidate
+}
+
+// SetCompleter replaces the source of completions. A nil completer restores
+// completion from the suggestions passed to NewInput.
+func (i *Input) SetCompleter(c Completer) {
+	i.stopCompletion()
+	i.completer = c
+	i.cachedFor = ""
+	i.cachedCandidates = nil
+	i.matchIndex = 0
+}
+
+// activeCompleter returns the configured completer, or the static list of
+// suggestions when none was set.
+func (i *Input) activeCompleter() Completer {
+	if i.completer != nil {
+		return i.completer
+	}
+	return StaticCompleter(i.suggestions)
+}
+
+// startCompletion runs CompleteAsync in the background for the current
+// buffer, cancelling the previous run. The result is delivered on
+// i.completions and applied by applyCompletion.
+func (i *Input) startCompletion(c AsyncCompleter) {
+	i.stopCompletion()
+	if i.completions == nil {
+		i.completions = make(chan asyncCompletion)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	i.cancelCompletion = cancel
+
+	buffer, cursor, results := slices.Clone(i.buffer), i.cursorPos, i.completions
+	go func() {
+		candidates := c.CompleteAsync(ctx, buffer, cursor)
+		select {
+		case results <- asyncCompletion{buffer: string(buffer), cursor: cursor, candidates: candidates}:
+		case <-ctx.Done():
+		}
+	}()
+}
+
+// stopCompletion cancels the running background completion, if any.
+func (i *Input) stopCompletion() {
+	if i.cancelCompletion != nil {
+		i.cancelCompletion()
+		i.cancelCompletion = nil
+	}
+}
+
+// applyCompletion shows background candidates if they are still current.
+func (i *Input) applyCompletion(result asyncCompletion) {
+	if result.buffer != string(i.buffer) || result.cursor != i.cursorPos {
+		return
+	}
+
+	i.stopCompletion()
+	i.cachedCandidates = validCandidates(result.candidates, len(i.buffer))
+	i.render()
+}
+
+// validCandidates drops candidates whose replacement range lies outside a
+// buffer of length n. Returns nil if none remain.
+func validCandidates(candidates []Candidate, n int) []Candidate {
+	valid := slices.DeleteFunc(slices.Clone(candidates), func(c Candidate) bool {
+		return c.Start < 0 || c.Start > c.End || c.End > n
+	})
+	if len(valid) == 0 {
+		return nil
+	}
+	return valid
+}
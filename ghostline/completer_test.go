@@ -0,0 +1,263 @@
+package ghostline
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flagCompleter offers flags for the subcommand before the cursor.
+var flagCompleter = CompleterFunc(func(buffer []rune, cursor int) []Candidate {
+	start := cursor
+	for start > 0 && buffer[start-1] != ' ' {
+		start--
+	}
+	if !strings.HasPrefix(string(buffer), "push ") || !strings.HasPrefix(string(buffer[start:cursor]), "-") {
+		return nil
+	}
+	return []Candidate{
+		{Text: "--force", Description: "overwrite remote", Start: start, End: cursor},
+		{Text: "--tags", Description: "push all tags", Start: start, End: cursor},
+	}
+})
+
+func TestStaticCompleter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Complete replaces the last word", func(t *testing.T) {
+		t.Parallel()
+
+		candidates := StaticCompleter{"commit", "checkout", "push"}.Complete([]rune("git co"), 6)
+
+		assert.Equal(t, []Candidate{
+			{Text: "commit", Start: 4, End: 6},
+			{Text: "checkout", Start: 4, End: 6},
+		}, candidates)
+	})
+
+	t.Run("Complete returns nil after a break character", func(t *testing.T) {
+		t.Parallel()
+
+		candidates := StaticCompleter{"commit"}.Complete([]rune("git "), 4)
+
+		assert.Nil(t, candidates)
+	})
+}
+
+func TestCompleter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("SetCompleter replaces the suggestions", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("push --f", 8)
+		input.suggestions = []string{"--fetch"}
+
+		input.SetCompleter(flagCompleter)
+
+		assert.Equal(t, []string{"--force", "--tags"}, input.getMatches())
+		assert.Equal(t, "orce", input.findGhost())
+	})
+
+	t.Run("SetCompleter with nil restores the suggestions", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("push --f", 8)
+		input.suggestions = []string{"--fetch"}
+		input.SetCompleter(flagCompleter)
+		input.getMatches()
+
+		input.SetCompleter(nil)
+
+		assert.Equal(t, []string{"--fetch"}, input.getMatches())
+	})
+
+	t.Run("completer sees the cursor position", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("push -- origin", 7)
+		input.SetCompleter(flagCompleter)
+
+		c, ok := input.selectedCandidate()
+
+		require.True(t, ok)
+		assert.Equal(t, Candidate{Text: "--force", Description: "overwrite remote", Start: 5, End: 7}, c)
+		assert.Empty(t, input.findGhost(), "ghost text is only drawn at the end of the buffer")
+	})
+
+	t.Run("acceptCandidate replaces the range mid-buffer", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("push -- origin", 7)
+		input.SetCompleter(flagCompleter)
+		c, _ := input.selectedCandidate()
+
+		input.acceptCandidate(c)
+
+		assert.Equal(t, "push --force origin", string(input.buffer))
+		assert.Equal(t, 12, input.cursorPos)
+	})
+
+	t.Run("handleTab accepts the selected candidate", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("push --", 7)
+		input.SetCompleter(flagCompleter)
+		input.matchIndex = 1
+
+		handleTab(input, nil)
+
+		assert.Equal(t, "push --tags", string(input.buffer))
+	})
+
+	t.Run("Tab completes in the middle of the buffer", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("push -- origin", 7)
+		input.handlers = defaultHandlers()
+		input.SetCompleter(flagCompleter)
+
+		pressKey(input, keyEscape, "[B")
+		pressKey(input, keyTab, "")
+
+		assert.Equal(t, "push --tags origin", string(input.buffer))
+		assert.Equal(t, 11, input.cursorPos)
+	})
+
+	t.Run("menu is shown in the middle of the buffer", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("push -- origin", 7)
+		input.SetCompleter(flagCompleter)
+
+		input.render()
+
+		out := input.out.(*bytes.Buffer).String()
+		assert.Contains(t, out, "overwrite remote")
+		assert.Contains(t, out, "push all tags")
+	})
+
+	t.Run("Tab ignores candidates that do not end at the cursor", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("git co main", 6)
+		input.handlers = defaultHandlers()
+		input.suggestions = []string{"mainline"}
+
+		pressKey(input, keyTab, "")
+
+		assert.Equal(t, "git co main", string(input.buffer))
+		assert.Equal(t, 6, input.cursorPos)
+	})
+
+	t.Run("acceptNextGhostWord accepts one word of the candidate", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("git ch", 6)
+		input.SetCompleter(CompleterFunc(func(buffer []rune, cursor int) []Candidate {
+			return []Candidate{{Text: "checkout main", Start: 4, End: cursor}}
+		}))
+
+		input.acceptNextGhostWord()
+
+		assert.Equal(t, "git checkout", string(input.buffer))
+		assert.Equal(t, 12, input.cursorPos)
+	})
+
+	t.Run("candidates outside the buffer are dropped", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("ab", 2)
+		input.SetCompleter(CompleterFunc(func([]rune, int) []Candidate {
+			return []Candidate{{Text: "x", Start: 1, End: 5}, {Text: "abc", Start: 0, End: 2}}
+		}))
+
+		assert.Equal(t, []string{"abc"}, input.getMatches())
+	})
+}
+
+func TestAsyncCompleter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("background candidates are applied when current", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("bu", 2)
+		input.SetCompleter(AsyncCompleterFunc(func(_ context.Context, buffer []rune, cursor int) []Candidate {
+			return StaticCompleter{"bucket-a", "bucket-b"}.Complete(buffer, cursor)
+		}))
+
+		assert.Nil(t, input.getMatches())
+		input.applyCompletion(<-input.completions)
+
+		assert.Equal(t, []string{"bucket-a", "bucket-b"}, input.getMatches())
+		assert.Contains(t, input.out.(*bytes.Buffer).String(), "cket-a")
+	})
+
+	t.Run("stale background candidates are ignored", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("bu", 2)
+		input.SetCompleter(AsyncCompleterFunc(func(context.Context, []rune, int) []Candidate {
+			return []Candidate{{Text: "bucket", Start: 0, End: 2}}
+		}))
+		input.getMatches()
+		result := <-input.completions
+
+		input.buffer, input.cursorPos = []rune("x"), 1
+		input.applyCompletion(result)
+
+		assert.Empty(t, input.cachedCandidates)
+	})
+
+	t.Run("buffer changes cancel the running completion", func(t *testing.T) {
+		t.Parallel()
+
+		started := make(chan context.Context, 2)
+		input := newTestInput("a", 1)
+		input.SetCompleter(AsyncCompleterFunc(func(ctx context.Context, _ []rune, _ int) []Candidate {
+			started <- ctx
+			<-ctx.Done()
+			return nil
+		}))
+		input.getMatches()
+		first := <-started
+
+		input.buffer, input.cursorPos = []rune("ab"), 2
+		input.getMatches()
+		second := <-started
+
+		assert.ErrorIs(t, first.Err(), context.Canceled)
+		assert.NoError(t, second.Err())
+		input.stopCompletion()
+		assert.ErrorIs(t, second.Err(), context.Canceled)
+	})
+}
+
+func TestKeyReader(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reads keys only on request", func(t *testing.T) {
+		t.Parallel()
+
+		reader := bufio.NewReader(strings.NewReader("ab\x1b[C"))
+		keys := newKeyReader(reader)
+		defer keys.close()
+
+		input := newTestInput("", 0)
+
+		first, _ := input.readKey(keys)
+		second, _ := input.readKey(keys)
+
+		assert.Equal(t, 'a', first)
+		assert.Equal(t, 'b', second)
+		rest, err := reader.ReadString('C')
+		require.NoError(t, err)
+		assert.Equal(t, "\x1b[C", rest)
+	})
+}
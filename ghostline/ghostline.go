@@ -19,8 +19,10 @@ package ghostline
 
 import (
 	"bufio"
+	"context"
 	"io"
 	"os"
+	"time"
 	"unicode"
 
 	"golang.org/x/term"
@@ -42,16 +44,44 @@ type Input struct {
 	matchIndex  int // current match index for Tab cycling
 	prompt      string
 	contPrompt  string // continuation prompt for multiline
-	prevLines   int    // previous line count for clearing
+	prevLines   int    // lines from the first prompt line to the cursor at the last render
+	menuOffset  int    // index of the first candidate visible in the completion menu
 	handlers    map[rune]keyHandler
 	history     *History
+	search      *historySearch // active Ctrl+R/Ctrl+S search, nil otherwise
 
-	// Match cache to avoid recomputing on every render
-	cachedMatches    []string
-	cachedMatchesFor string // buffer state when cache was computed
+	completer   Completer   // source of candidates; nil = StaticCompleter(suggestions)
+	highlighter Highlighter // styles the buffer; nil draws it unstyled
+	validator   Validator   // decides whether Enter submits; nil always submits
 
-	in  io.Reader
-	out io.Writer
+	validationErr error // error shown below the input until the next keystroke
+
+	undo      []editState // buffer states restored by undo, oldest first
+	redo      []editState // buffer states restored by redo, latest undone last
+	undoGroup bool        // merge the next change into the latest undo step
+	undoing   bool        // the current keystroke restored an undo or redo step
+	vi        *viState    // vi mode state; nil in emacs mode
+
+	killRing  [][]rune // text removed by kill commands, newest last
+	yankIndex int      // kill ring entry inserted by the last yank
+	yankStart int      // buffer range of the text inserted by the last yank
+	yankEnd   int
+
+	lastCommand command // kind of command run by the previous keystroke
+	thisCommand command // kind of command run by the current keystroke
+
+	// Candidate cache to avoid recomputing on every render
+	cachedCandidates []Candidate
+	cachedFor        string // buffer state when cache was computed
+	cachedCursor     int    // cursor position when cache was computed
+
+	// Background completion for an AsyncCompleter
+	completions      chan asyncCompletion
+	cancelCompletion context.CancelFunc // cancels the running CompleteAsync call
+
+	in   io.Reader
+	out  io.Writer
+	keys *keyReader // reads keystrokes while Readline runs; nil otherwise
 
 	fd       int
 	oldState *term.State
@@ -72,6 +102,8 @@ type Input struct {
 //
 //	// Using custom streams for testing
 //	input := ghostline.NewInput(suggestions, mockReader, mockWriter)
+//
+// Use SetCompleter for completion that depends on more than the last word.
 func NewInput(suggestions []string, in io.Reader, out io.Writer) *Input {
 	if in == nil {
 		in = os.Stdin
@@ -101,6 +133,20 @@ func (i *Input) AddHistory(line string) {
 	i.history.Add(line)
 }
 
+// SetHistory replaces the command history, e.g. with one loaded from a file
+// by OpenHistory. A nil history is replaced by an empty one.
+func (i *Input) SetHistory(h *History) {
+	if h == nil {
+		h = NewHistory()
+	}
+	i.history = h
+}
+
+// History returns the command history used by Readline.
+func (i *Input) History() *History {
+	return i.history
+}
+
 // Readline reads a line of input with interactive ghost text suggestions.
 // Returns the entered text and nil on success, or an error if aborted.
 // Places the terminal in raw mode for the duration of input.
@@ -110,10 +156,19 @@ func (i *Input) AddHistory(line string) {
 //
 // Keyboard controls:
 //   - Tab: accept the current ghost text suggestion
-//   - Enter: submit the current input
+//   - Enter: submit the current input, or start a new line if the Validator
+//     reports it incomplete
 //   - Backspace/Delete: remove the last character
 //   - Ctrl+C: abort input (returns ErrInterrupted)
 //   - Ctrl+D: abort input when buffer is empty (returns ErrEOF)
+//   - Ctrl+R/Ctrl+S: search history backward/forward (Ctrl+G aborts the search)
+//   - Ctrl+K/Ctrl+U/Ctrl+W: kill text into the kill ring; Ctrl+Y yanks it back,
+//     Alt+Y then cycles to older kills
+//   - Ctrl+_ or Ctrl+Z: undo, Alt+/: redo
+//   - Escape: switch to normal mode when vi editing mode is on (see SetEditMode)
+//
+// Pasted text is inserted as one edit with its line breaks kept, without
+// submitting, in terminals supporting bracketed paste.
 //
 // Example:
 //
@@ -144,7 +199,15 @@ func (i *Input) Readline(prompt string) (string, error) {
 	i.buffer = []rune{}
 	i.cursorPos = 0
 	i.prevLines = 1
+	i.menuOffset = 0
+	i.validationErr = nil
+	i.search = nil
+	i.resetUndo()
+	i.lastCommand, i.thisCommand = commandOther, commandOther
 	i.history.Reset("")
+	if i.vi != nil {
+		i.vi.reset()
+	}
 
 	if err := i.enableRawMode(); err != nil {
 		return "", err
@@ -152,27 +215,147 @@ func (i *Input) Readline(prompt string) (string, error) {
 	defer i.disableRawMode()
 
 	reader := bufio.NewReader(i.in)
+	keys := newKeyReader(reader)
+	defer keys.close()
+	i.keys = keys
+	defer func() { i.keys = nil }()
+	defer i.stopCompletion()
 	i.render()
 
 	for {
-		r, _, err := reader.ReadRune()
+		r, err := i.readKey(keys)
 		if err != nil {
 			return "", err
 		}
+		i.validationErr = nil
+
+		before, searching := i.snapshot(), i.search != nil
+		result, act := i.handleKey(r, reader)
+		if !searching && i.search == nil {
+			i.trackUndo(before)
+		}
+		i.lastCommand, i.thisCommand = i.thisCommand, commandOther
+
+		if act == actionContinue {
+			continue
+		}
+		return result, actionErrors[act]
+	}
+}
+
+// handleKey dispatches a keystroke to the active history search, the vi
+// normal mode commands or the key handlers, and inserts printable characters
+// that no handler claims.
+func (i *Input) handleKey(r rune, reader *bufio.Reader) (string, action) {
+	if i.search != nil && i.handleSearchKey(r) {
+		return "", actionContinue
+	}
+
+	if i.vi != nil && i.vi.normal {
+		return i.handleViNormal(r, reader)
+	}
+
+	if handler, exists := i.handlers[r]; exists {
+		return handler(i, reader)
+	}
+
+	if unicode.IsPrint(r) {
+		i.buffer = append(i.buffer[:i.cursorPos], append([]rune{r}, i.buffer[i.cursorPos:]...)...)
+		i.cursorPos++
+		i.matchIndex = 0
+		i.thisCommand = commandInsert
+		i.render()
+	}
+	return "", actionContinue
+}
+
+// readKey waits for the next keystroke, showing background completions that
+// arrive in the meantime.
+func (i *Input) readKey(keys *keyReader) (rune, error) {
+	for keys.waiting {
+		select {
+		case <-keys.ready:
+			keys.waiting = false
+		case result := <-i.completions:
+			i.applyCompletion(result)
+		}
+	}
 
-		if handler, exists := i.handlers[r]; exists {
-			result, act := handler(i, reader)
-			if act == actionContinue {
+	keys.request()
+	for {
+		select {
+		case key := <-keys.keys:
+			return key.r, key.err
+		case result := <-i.completions:
+			i.applyCompletion(result)
+		}
+	}
+}
+
+// keyResult is a keystroke read by a keyReader.
+type keyResult struct {
+	r   rune
+	err error
+}
+
+// keyReader reads keystrokes on a separate goroutine so Readline can wait for
+// background completions at the same time. A key is only read on request, so
+// the goroutine never touches the reader while a handler reads the rest of an
+// escape sequence from it, and no keystroke is consumed after Readline returns.
+type keyReader struct {
+	requests chan bool      // true asks to wait for input instead of reading a key
+	keys     chan keyResult // delivers one key per request
+	ready    chan struct{}  // signalled once input is available after a wait request
+	waiting  bool           // a wait request has not been signalled on ready yet
+}
+
+// newKeyReader starts reading keystrokes from reader on request.
+func newKeyReader(reader *bufio.Reader) *keyReader {
+	k := &keyReader{
+		requests: make(chan bool),
+		keys:     make(chan keyResult, 1),
+		ready:    make(chan struct{}, 1),
+	}
+	go func() {
+		for wait := range k.requests {
+			if wait {
+				_, _ = reader.Peek(1)
+				k.ready <- struct{}{}
 				continue
 			}
-			return result, actionErrors[act]
+			r, _, err := reader.ReadRune()
+			k.keys <- keyResult{r: r, err: err}
 		}
+	}()
+	return k
+}
 
-		if unicode.IsPrint(r) {
-			i.buffer = append(i.buffer[:i.cursorPos], append([]rune{r}, i.buffer[i.cursorPos:]...)...)
-			i.cursorPos++
-			i.matchIndex = 0
-			i.render()
-		}
+// request asks for the next keystroke to be delivered on k.keys.
+// The caller must first receive the signal of a pending wait request.
+func (k *keyReader) request() {
+	k.requests <- false
+}
+
+// wait reports whether input becomes available within timeout. A wait that
+// times out stays pending, and its signal is consumed by the next wait or key.
+func (k *keyReader) wait(timeout time.Duration) bool {
+	if !k.waiting {
+		k.requests <- true
+		k.waiting = true
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-k.ready:
+		k.waiting = false
+		return true
+	case <-timer.C:
+		return false
 	}
 }
+
+// close stops the reading goroutine once its pending read, if any, completes.
+func (k *keyReader) close() {
+	close(k.requests)
+}
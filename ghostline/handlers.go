@@ -2,7 +2,9 @@ package ghostline
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"time"
 )
 
 // Terminal control character constants (ASCII codes).
@@ -12,13 +14,19 @@ const (
 	keyCtrlC     = 3   // Ctrl+C: interrupt/abort
 	keyCtrlD     = 4   // Ctrl+D: EOF on empty buffer
 	keyCtrlE     = 5   // Ctrl+E: move to line end
+	keyCtrlG     = 7   // Ctrl+G: abort history search
 	keyTab       = 9   // Tab: accept suggestion
 	keyCtrlJ     = 10  // Ctrl+J: insert newline
 	keyCtrlK     = 11  // Ctrl+K: kill to end of line
 	keyEnter     = 13  // Enter: submit input
+	keyCtrlR     = 18  // Ctrl+R: reverse history search
+	keyCtrlS     = 19  // Ctrl+S: forward history search
 	keyCtrlU     = 21  // Ctrl+U: kill to start of line
 	keyCtrlW     = 23  // Ctrl+W: delete word backward
-	keyEscape    = 27  // Escape: start of CSI sequences
+	keyCtrlY     = 25  // Ctrl+Y: yank killed text
+	keyCtrlZ     = 26  // Ctrl+Z: undo
+	keyEscape    = 27  // Escape: start of CSI sequences and Alt+key
+	keyCtrlUnder = 31  // Ctrl+_: undo
 	keyBackspace = 127 // Backspace: delete previous char
 	keyDelete    = 8   // Delete: alternate backspace
 )
@@ -60,25 +68,34 @@ func handleCtrlD(i *Input, reader *bufio.Reader) (string, action) {
 	return "", actionContinue
 }
 
-// handleTab accepts the current ghost suggestion when cursor is at end.
-// Replaces the last word with the full suggestion text.
+// handleTab accepts the selected candidate, at the end of the buffer or in
+// the middle when the candidate's range ends at the cursor.
+// Replaces the candidate's range with the full suggestion text.
 func handleTab(i *Input, reader *bufio.Reader) (string, action) {
-	if i.cursorPos == len(i.buffer) {
-		if match := i.findMatch(); match != "" {
-			// Accept current suggestion
-			start := i.lastWordStart()
-			i.buffer = append(i.buffer[:start], []rune(match)...)
-			i.cursorPos = len(i.buffer)
-			i.matchIndex = 0
-			i.render()
-		}
+	if c, ok := i.selectedCandidate(); ok && i.completing() {
+		i.acceptCandidate(c)
 	}
 	return "", actionContinue
 }
 
 // handleEnter submits the current buffer contents.
+// With a Validator, incomplete input continues on a new line and invalid
+// input stays open with the error shown below it.
 func handleEnter(i *Input, reader *bufio.Reader) (string, action) {
-	// Move cursor to column 1, then to next line
+	if i.validator != nil {
+		err := i.validator.Validate(i.buffer)
+		if errors.Is(err, ErrIncomplete) {
+			return handleCtrlJ(i, reader)
+		}
+		if err != nil {
+			i.validationErr = err
+			i.render()
+			return "", actionContinue
+		}
+	}
+
+	// Drop hints, then move cursor to column 1 of the next line
+	i.renderFinal()
 	_, _ = fmt.Fprint(i.out, "\r\n")
 	return string(i.buffer), actionSubmit
 }
@@ -94,12 +111,26 @@ func handleBackspace(i *Input, reader *bufio.Reader) (string, action) {
 	return "", actionContinue
 }
 
-// handleEscape processes CSI escape sequences (arrow keys, delete, etc.).
-// Reads the sequence bytes and delegates to the appropriate CSI handler.
+// handleEscape processes CSI escape sequences (arrow keys, delete, etc.)
+// and Alt+key combinations, which terminals send as Escape followed by the key.
+// Reads the sequence bytes and delegates to the appropriate handler.
+// In vi insert mode a lone Escape, with no sequence bytes following it,
+// switches to normal mode.
 func handleEscape(i *Input, reader *bufio.Reader) (string, action) {
+	if i.vi != nil && !i.sequenceFollows(reader) {
+		i.enterNormalMode()
+		return "", actionContinue
+	}
+
 	// Read escape sequence: ESC [ <code>
 	b1, err := reader.ReadByte()
-	if err != nil || b1 != '[' {
+	if err != nil {
+		return "", actionContinue
+	}
+	if b1 != '[' {
+		if handler, ok := altHandlers[b1]; ok {
+			return handler(i, reader)
+		}
 		return "", actionContinue
 	}
 
@@ -112,6 +143,29 @@ func handleEscape(i *Input, reader *bufio.Reader) (string, action) {
 	return "", actionContinue
 }
 
+// escTimeout is how long to wait for the rest of an escape sequence before
+// taking Escape as a key of its own, like readline's keyseq-timeout. Over ssh
+// or a slow pty an arrow key's ESC and "[A" can arrive in separate reads.
+const escTimeout = 50 * time.Millisecond
+
+// sequenceFollows reports whether more bytes of an escape sequence follow the
+// Escape just read: already buffered, or arriving within escTimeout.
+func (i *Input) sequenceFollows(reader *bufio.Reader) bool {
+	if reader.Buffered() > 0 {
+		return true
+	}
+	if i.keys == nil {
+		return false
+	}
+	return i.keys.wait(escTimeout)
+}
+
+// altHandlers maps the key following Escape to its Alt+key handler.
+var altHandlers = map[byte]keyHandler{
+	'y': handleAltY,
+	'/': handleRedo,
+}
+
 // csiHandler processes a CSI (Control Sequence Introducer) escape sequence.
 type csiHandler func(i *Input, reader *bufio.Reader)
 
@@ -122,6 +176,7 @@ var csiHandlers = map[byte]csiHandler{
 	'C': handleRightArrow,
 	'D': handleLeftArrow,
 	'3': handleDelete,
+	'2': handlePaste,
 }
 
 // handleCSI dispatches CSI escape sequences to specific handlers.
@@ -168,14 +223,11 @@ func (i *Input) handleExtendedCSI(reader *bufio.Reader) {
 // handleUpArrow cycles suggestions, navigates history, or moves up a line.
 // Behavior depends on cursor position and available matches.
 func handleUpArrow(i *Input, _ *bufio.Reader) {
-	// At end of buffer with multiple matches: cycle suggestions
-	if i.cursorPos == len(i.buffer) {
-		matches := i.getMatches()
-		if len(matches) > 1 {
-			i.matchIndex = (i.matchIndex - 1 + len(matches)) % len(matches)
-			i.render()
-			return
-		}
+	// With multiple matches at the cursor: cycle suggestions
+	if matches := i.getMatches(); len(matches) > 1 && i.completing() {
+		i.matchIndex = (i.matchIndex - 1 + len(matches)) % len(matches)
+		i.render()
+		return
 	}
 
 	// Find current line start and check if we're on first line
@@ -209,14 +261,11 @@ func handleUpArrow(i *Input, _ *bufio.Reader) {
 // handleDownArrow cycles suggestions, navigates history, or moves down a line.
 // Behavior depends on cursor position and available matches.
 func handleDownArrow(i *Input, _ *bufio.Reader) {
-	// At end of buffer with multiple matches: cycle suggestions
-	if i.cursorPos == len(i.buffer) {
-		matches := i.getMatches()
-		if len(matches) > 1 {
-			i.matchIndex = (i.matchIndex + 1) % len(matches)
-			i.render()
-			return
-		}
+	// With multiple matches at the cursor: cycle suggestions
+	if matches := i.getMatches(); len(matches) > 1 && i.completing() {
+		i.matchIndex = (i.matchIndex + 1) % len(matches)
+		i.render()
+		return
 	}
 
 	// Find current line end and check if we're on last line
@@ -306,13 +355,10 @@ func (i *Input) acceptNextGhostWord() {
 		return
 	}
 
-	match := i.findMatch()
-	lastWord := extractLastWord(string(i.buffer))
-	accepted := match[:len(lastWord)+wordEnd]
-	start := i.lastWordStart()
+	c, _ := i.selectedCandidate()
+	typed := string([]rune(c.Text)[:c.End-c.Start])
 
-	i.buffer = append(i.buffer[:start], []rune(accepted)...)
-	i.cursorPos = len(i.buffer)
+	i.replaceRange(c.Start, c.End, typed+ghost[:wordEnd])
 	i.matchIndex = 0
 	i.render()
 }
@@ -370,13 +416,9 @@ func handleRightArrow(i *Input, _ *bufio.Reader) {
 	if i.cursorPos < len(i.buffer) {
 		i.cursorPos++
 		i.render()
-	} else if match := i.findMatch(); match != "" {
+	} else if c, ok := i.selectedCandidate(); ok {
 		// At end of buffer: accept current suggestion
-		start := i.lastWordStart()
-		i.buffer = append(i.buffer[:start], []rune(match)...)
-		i.cursorPos = len(i.buffer)
-		i.matchIndex = 0
-		i.render()
+		i.acceptCandidate(c)
 	}
 }
 
@@ -423,6 +465,7 @@ func handleCtrlE(i *Input, reader *bufio.Reader) (string, action) {
 // handleCtrlK deletes text from cursor to end of line (kill forward).
 func handleCtrlK(i *Input, reader *bufio.Reader) (string, action) {
 	lineEnd := i.findLineEnd()
+	i.kill(i.buffer[i.cursorPos:lineEnd], false)
 	i.buffer = append(i.buffer[:i.cursorPos], i.buffer[lineEnd:]...)
 	i.matchIndex = 0
 	i.render()
@@ -432,6 +475,7 @@ func handleCtrlK(i *Input, reader *bufio.Reader) (string, action) {
 // handleCtrlU deletes text from start of line to cursor (kill backward).
 func handleCtrlU(i *Input, reader *bufio.Reader) (string, action) {
 	lineStart := i.findLineStart()
+	i.kill(i.buffer[lineStart:i.cursorPos], true)
 	i.buffer = append(i.buffer[:lineStart], i.buffer[i.cursorPos:]...)
 	i.cursorPos = lineStart
 	i.matchIndex = 0
@@ -456,6 +500,7 @@ func handleCtrlW(i *Input, reader *bufio.Reader) (string, action) {
 		pos--
 	}
 
+	i.kill(i.buffer[pos:i.cursorPos], true)
 	i.buffer = append(i.buffer[:pos], i.buffer[i.cursorPos:]...)
 	i.cursorPos = pos
 	i.matchIndex = 0
@@ -481,8 +526,13 @@ func defaultHandlers() map[rune]keyHandler {
 		keyCtrlE:     handleCtrlE,
 		keyCtrlJ:     handleCtrlJ,
 		keyCtrlK:     handleCtrlK,
+		keyCtrlR:     handleCtrlR,
+		keyCtrlS:     handleCtrlS,
 		keyCtrlU:     handleCtrlU,
 		keyCtrlW:     handleCtrlW,
+		keyCtrlY:     handleCtrlY,
+		keyCtrlZ:     handleUndo,
+		keyCtrlUnder: handleUndo,
 		keyTab:       handleTab,
 		keyEnter:     handleEnter,
 		keyBackspace: handleBackspace,
@@ -1,7 +1,9 @@
 package ghostline
 
 import (
+	"bufio"
 	"bytes"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -13,6 +15,28 @@ func newTestInput(buffer string, cursorPos int) *Input {
 		cursorPos: cursorPos,
 		history:   NewHistory(),
 		out:       &bytes.Buffer{},
+		fd:        -1,
+	}
+}
+
+// pressKey feeds a keystroke to input as Readline does, tracking undo steps
+// and the command run. rest holds the bytes that arrived with the key, such as
+// the remainder of an escape sequence.
+func pressKey(input *Input, r rune, rest string) {
+	reader := bufio.NewReader(strings.NewReader(rest))
+	_, _ = reader.Peek(len(rest))
+
+	before := input.snapshot()
+	input.handleKey(r, reader)
+	input.trackUndo(before)
+	input.lastCommand, input.thisCommand = input.thisCommand, commandOther
+}
+
+// pressKeys feeds keys to input one keystroke at a time; Escape is a lone
+// keypress.
+func pressKeys(input *Input, keys string) {
+	for _, r := range keys {
+		pressKey(input, r, "")
 	}
 }
 
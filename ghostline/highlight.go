@@ -0,0 +1,153 @@
+package ghostline
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Color is a 24-bit terminal color. The zero value is the terminal's default.
+type Color struct {
+	R, G, B uint8
+	set     bool
+}
+
+// RGB returns the color with the given red, green and blue components.
+func RGB(r, g, b uint8) Color {
+	return Color{R: r, G: g, B: b, set: true}
+}
+
+// Style describes how a span of the input is drawn.
+// The zero value draws text unstyled.
+type Style struct {
+	Foreground Color
+	Background Color
+	Bold       bool
+	Italic     bool
+	Underline  bool
+}
+
+// sgr returns the escape sequence selecting the style, or "" for the zero style.
+//
+// Uses ANSI SGR (Select Graphic Rendition) parameters:
+//   - 1, 3, 4: bold, italic, underline
+//   - 38;2;r;g;b / 48;2;r;g;b: 24-bit foreground / background color
+func (s Style) sgr() string {
+	var params []string
+	if s.Bold {
+		params = append(params, "1")
+	}
+	if s.Italic {
+		params = append(params, "3")
+	}
+	if s.Underline {
+		params = append(params, "4")
+	}
+	if c := s.Foreground; c.set {
+		params = append(params, fmt.Sprintf("38;2;%d;%d;%d", c.R, c.G, c.B))
+	}
+	if c := s.Background; c.set {
+		params = append(params, fmt.Sprintf("48;2;%d;%d;%d", c.R, c.G, c.B))
+	}
+	if len(params) == 0 {
+		return ""
+	}
+	return "\033[" + strings.Join(params, ";") + "m"
+}
+
+// Span styles the buffer runes in [Start, End).
+type Span struct {
+	Start int // first styled rune index in the buffer
+	End   int // rune index after the last styled rune
+	Style Style
+}
+
+// Highlighter styles the input while it is typed, e.g. coloring keywords,
+// strings and errors. Highlight receives the whole buffer and returns spans
+// of rune indices; runes outside every span are drawn unstyled, and where
+// spans overlap the later one wins. Spans reaching past the buffer are clipped.
+//
+// Highlight is called on every redraw, so it should be fast.
+// It must not modify or retain buffer.
+type Highlighter interface {
+	Highlight(buffer []rune) []Span
+}
+
+// HighlighterFunc adapts an ordinary function to the Highlighter interface.
+//
+// Example:
+//
+//	keyword := ghostline.Style{Foreground: ghostline.RGB(198, 120, 221), Bold: true}
+//	input.SetHighlighter(ghostline.HighlighterFunc(func(buffer []rune) []ghostline.Span {
+//		if strings.HasPrefix(string(buffer), "select") {
+//			return []ghostline.Span{{Start: 0, End: 6, Style: keyword}}
+//		}
+//		return nil
+//	}))
+type HighlighterFunc func(buffer []rune) []Span
+
+// Highlight calls f(buffer).
+func (f HighlighterFunc) Highlight(buffer []rune) []Span {
+	return f(buffer)
+}
+
+// SetHighlighter sets the hook styling the input buffer.
+// A nil highlighter draws the input unstyled.
+func (i *Input) SetHighlighter(h Highlighter) {
+	i.highlighter = h
+}
+
+// searchMatchStyle marks the runes matched by a history search (bold, color: #facc15).
+var searchMatchStyle = Style{Foreground: RGB(250, 204, 21), Bold: true}
+
+// bufferStyles returns the style of every buffer rune: the runes matched by an
+// active history search, or else the spans produced by the highlighter.
+// Returns nil when the buffer is drawn unstyled.
+func (i *Input) bufferStyles() []Style {
+	var spans []Span
+	switch {
+	case i.search != nil:
+		for _, pos := range i.search.positions {
+			spans = append(spans, Span{Start: pos, End: pos + 1, Style: searchMatchStyle})
+		}
+	case i.highlighter != nil:
+		spans = i.highlighter.Highlight(i.buffer)
+	}
+	if len(spans) == 0 {
+		return nil
+	}
+
+	styles := make([]Style, len(i.buffer))
+	for _, span := range spans {
+		for pos := max(span.Start, 0); pos < min(span.End, len(styles)); pos++ {
+			styles[pos] = span.Style
+		}
+	}
+	return styles
+}
+
+// styleLine returns line with escape sequences applying styles, which holds
+// the style of each rune of line. Consecutive runes with the same style share
+// one escape sequence; only styled runs are followed by a reset.
+func styleLine(line []rune, styles []Style) string {
+	if len(styles) == 0 {
+		return string(line)
+	}
+
+	var b strings.Builder
+	for start := 0; start < len(line); {
+		end := start + 1
+		for end < len(line) && styles[end] == styles[start] {
+			end++
+		}
+
+		if sgr := styles[start].sgr(); sgr != "" {
+			b.WriteString(sgr)
+			b.WriteString(string(line[start:end]))
+			b.WriteString("\033[0m")
+		} else {
+			b.WriteString(string(line[start:end]))
+		}
+		start = end
+	}
+	return b.String()
+}
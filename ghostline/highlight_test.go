@@ -0,0 +1,110 @@
+package ghostline
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStyle(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		style Style
+		want  string
+	}{
+		{"zero style", Style{}, ""},
+		{"bold", Style{Bold: true}, "\033[1m"},
+		{"foreground", Style{Foreground: RGB(255, 0, 128)}, "\033[38;2;255;0;128m"},
+		{"black foreground", Style{Foreground: RGB(0, 0, 0)}, "\033[38;2;0;0;0m"},
+		{"all attributes", Style{Foreground: RGB(1, 2, 3), Background: RGB(4, 5, 6), Bold: true, Italic: true, Underline: true},
+			"\033[1;3;4;38;2;1;2;3;48;2;4;5;6m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.style.sgr())
+		})
+	}
+}
+
+func TestHighlighter(t *testing.T) {
+	t.Parallel()
+
+	keyword := Style{Foreground: RGB(198, 120, 221)}
+	str := Style{Foreground: RGB(152, 195, 121)}
+	sql := HighlighterFunc(func(buffer []rune) []Span {
+		var spans []Span
+		text := string(buffer)
+		if strings.HasPrefix(text, "select") {
+			spans = append(spans, Span{Start: 0, End: 6, Style: keyword})
+		}
+		if start := strings.IndexRune(text, '\''); start >= 0 {
+			spans = append(spans, Span{Start: len([]rune(text[:start])), End: len(buffer) + 10, Style: str})
+		}
+		return spans
+	})
+
+	t.Run("styleLine groups runs of one style", func(t *testing.T) {
+		t.Parallel()
+
+		styles := []Style{keyword, keyword, {}, str}
+
+		got := styleLine([]rune("ab c"), styles)
+
+		assert.Equal(t, "\033[38;2;198;120;221mab\033[0m \033[38;2;152;195;121mc\033[0m", got)
+	})
+
+	t.Run("bufferStyles clips spans and applies later spans last", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("ab", 2)
+		input.SetHighlighter(HighlighterFunc(func([]rune) []Span {
+			return []Span{{Start: -3, End: 9, Style: keyword}, {Start: 1, End: 2, Style: str}}
+		}))
+
+		styles := input.bufferStyles()
+
+		assert.Equal(t, []Style{keyword, str}, styles)
+	})
+
+	t.Run("render styles the buffer", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("select 'x'", 10)
+		input.prompt = "> "
+		input.SetHighlighter(sql)
+		out := input.out.(*bytes.Buffer)
+
+		input.render()
+
+		assert.Equal(t, "\r\033[J> \033[38;2;198;120;221mselect\033[0m \033[38;2;152;195;121m'x'\033[0m\r\033[13G", out.String())
+	})
+
+	t.Run("render keeps styles across lines", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("select\n'a\nb'", 2)
+		input.prompt, input.contPrompt = "> ", ". "
+		input.SetHighlighter(sql)
+		out := input.out.(*bytes.Buffer)
+
+		input.render()
+
+		assert.Contains(t, out.String(), "\n\r. \033[38;2;152;195;121m'a\033[0m\n\r. \033[38;2;152;195;121mb'\033[0m")
+		assert.True(t, strings.HasSuffix(out.String(), "\033[2A\r\033[5G"))
+	})
+
+	t.Run("nil highlighter draws plain text", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("select", 6)
+		input.SetHighlighter(nil)
+
+		assert.Nil(t, input.bufferStyles())
+	})
+}
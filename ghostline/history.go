@@ -1,25 +1,101 @@
 package ghostline
 
-import "strings"
+import (
+	"bufio"
+	"errors"
+	"os"
+	"slices"
+	"strings"
+)
+
+// DedupPolicy controls which duplicate entries History keeps.
+type DedupPolicy int
+
+const (
+	// DedupConsecutive ignores an entry equal to the previous one (the default).
+	DedupConsecutive DedupPolicy = iota
+	// DedupNone keeps every entry, including repeats.
+	DedupNone
+	// DedupAll removes older copies of an entry, so each command appears once,
+	// at the position where it was last used.
+	DedupAll
+)
+
+// HistoryOption configures a History created by NewHistory or OpenHistory.
+type HistoryOption func(*History)
+
+// WithMaxEntries limits history to the n most recent entries, both in memory
+// and in the history file. Zero or a negative n means no limit.
+func WithMaxEntries(n int) HistoryOption {
+	return func(h *History) {
+		h.maxEntries = max(n, 0)
+	}
+}
+
+// WithDedup sets the policy for duplicate entries.
+func WithDedup(policy DedupPolicy) HistoryOption {
+	return func(h *History) {
+		h.dedup = policy
+	}
+}
 
 // History stores command history with up/down arrow navigation.
 // Preserves the user's in-progress input when navigating through entries.
+//
+// History can be persisted to a file with Load and Save, or kept in sync with
+// one by OpenHistory. Files are locked while being read or written, so several
+// processes can share one history file.
 type History struct {
 	entries []string // chronological list of past commands
 	pos     int      // navigation position (-1 = current input, 0+ = history index)
 	current string   // saves in-progress input during navigation
+
+	maxEntries int         // maximum number of entries kept; 0 means unlimited
+	dedup      DedupPolicy // which duplicates are dropped by Add
+
+	path      string   // history file appended on Add; empty when not in append mode
+	fileLines int      // estimated number of entries in the file at path
+	unsaved   []string // entries added since the last Load or Save
+	err       error    // first error from appending to the file
 }
 
 // NewHistory creates an empty history.
-func NewHistory() *History {
-	return &History{
+func NewHistory(opts ...HistoryOption) *History {
+	h := &History{
 		entries: []string{},
 		pos:     -1,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// OpenHistory creates a history loaded from the file at path and appends every
+// added entry to that file immediately, so entries survive crashes and are seen
+// by other processes sharing the file when they next load it.
+// A missing file is not an error; it is created on the first Add.
+//
+// Example:
+//
+//	history, err := ghostline.OpenHistory(filepath.Join(home, ".myrepl_history"),
+//		ghostline.WithMaxEntries(1000), ghostline.WithDedup(ghostline.DedupAll))
+//	if err != nil {
+//		return err
+//	}
+//	input.SetHistory(history)
+func OpenHistory(path string, opts ...HistoryOption) (*History, error) {
+	h := NewHistory(opts...)
+	if err := h.Load(path); err != nil {
+		return nil, err
+	}
+	h.path = path
+	return h, nil
 }
 
 // Add appends an entry to history after trimming whitespace.
-// Ignores empty strings and consecutive duplicates.
+// Ignores empty strings, and duplicates according to the dedup policy.
+// In append mode the entry is also written to the history file; see Err.
 func (h *History) Add(entry string) {
 	entry = strings.TrimSpace(entry)
 	if entry == "" {
@@ -27,12 +103,20 @@ func (h *History) Add(entry string) {
 	}
 
 	// Don't add duplicates of the last entry
-	if len(h.entries) > 0 && h.entries[len(h.entries)-1] == entry {
+	if h.dedup != DedupNone && len(h.entries) > 0 && h.entries[len(h.entries)-1] == entry {
 		return
 	}
 
-	h.entries = append(h.entries, entry)
+	h.entries = h.limit(h.deduplicate(append(h.entries, entry)))
 	h.pos = -1
+
+	if h.path == "" {
+		h.unsaved = append(h.unsaved, entry)
+		return
+	}
+	if err := h.appendFile(entry); err != nil && h.err == nil {
+		h.err = err
+	}
 }
 
 // Len returns the number of stored history entries.
@@ -40,6 +124,17 @@ func (h *History) Len() int {
 	return len(h.entries)
 }
 
+// Entries returns a copy of the stored entries, oldest first.
+func (h *History) Entries() []string {
+	return slices.Clone(h.entries)
+}
+
+// Err returns the first error that occurred while appending entries to the
+// history file in append mode, or nil.
+func (h *History) Err() error {
+	return h.err
+}
+
 // Reset prepares history for a new readline session.
 // Stores the current input for restoration after navigation.
 func (h *History) Reset(current string) {
@@ -89,3 +184,190 @@ func (h *History) Next() (string, bool) {
 	h.pos = -1
 	return h.current, true
 }
+
+// Load replaces the entries with those stored in the file at path,
+// applying the dedup policy and entry limit. A missing file leaves history empty.
+func (h *History) Load(path string) error {
+	unlock, err := lockHistory(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entries, err := readHistory(path)
+	if err != nil {
+		return err
+	}
+
+	h.fileLines = len(entries)
+	h.entries = h.limit(h.deduplicate(entries))
+	h.unsaved = nil
+	h.pos = -1
+	return nil
+}
+
+// Save writes history to the file at path, keeping entries other processes
+// saved there since this history was loaded: the file ends up with its current
+// entries followed by those added here since the last Load or Save, with the
+// dedup policy and entry limit applied.
+func (h *History) Save(path string) error {
+	unlock, err := lockHistory(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entries, err := readHistory(path)
+	if err != nil {
+		return err
+	}
+
+	entries = h.limit(h.deduplicate(append(entries, h.unsaved...)))
+	if err := writeHistory(path, entries); err != nil {
+		return err
+	}
+
+	h.fileLines = len(entries)
+	h.unsaved = nil
+	return nil
+}
+
+// appendFile adds entry to the end of the history file, compacting the file
+// once it holds twice as many entries as the limit.
+func (h *History) appendFile(entry string) error {
+	unlock, err := lockHistory(h.path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	f, err := os.OpenFile(h.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = f.WriteString(escapeEntry(entry) + "\n")
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	h.fileLines++
+	if h.maxEntries == 0 || h.fileLines <= 2*h.maxEntries {
+		return nil
+	}
+
+	entries, err := readHistory(h.path)
+	if err != nil {
+		return err
+	}
+	entries = h.limit(h.deduplicate(entries))
+	h.fileLines = len(entries)
+	return writeHistory(h.path, entries)
+}
+
+// deduplicate applies the dedup policy to entries, reusing its storage.
+func (h *History) deduplicate(entries []string) []string {
+	switch h.dedup {
+	case DedupAll:
+		// Keep the last occurrence of each entry, packing kept entries
+		// toward the end of the slice.
+		seen := make(map[string]bool, len(entries))
+		start := len(entries)
+		for idx := len(entries) - 1; idx >= 0; idx-- {
+			if !seen[entries[idx]] {
+				seen[entries[idx]] = true
+				start--
+				entries[start] = entries[idx]
+			}
+		}
+		return entries[start:]
+	case DedupConsecutive:
+		return slices.Compact(entries)
+	}
+	return entries
+}
+
+// limit drops the oldest entries beyond the maximum.
+func (h *History) limit(entries []string) []string {
+	if h.maxEntries > 0 && len(entries) > h.maxEntries {
+		return slices.Clone(entries[len(entries)-h.maxEntries:])
+	}
+	return entries
+}
+
+// readHistory returns the entries stored in the file at path.
+// A missing file has no entries.
+func readHistory(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	entries := []string{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			entries = append(entries, unescapeEntry(line))
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// writeHistory replaces the file at path with entries. The content goes to a
+// temporary file first, so readers never see a partially written history.
+func writeHistory(path string, entries []string) error {
+	var b strings.Builder
+	for _, entry := range entries {
+		b.WriteString(escapeEntry(entry))
+		b.WriteByte('\n')
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// escapeEntry encodes an entry as a single line: backslashes and line breaks
+// become \\, \n and \r.
+func escapeEntry(entry string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`, "\r", `\r`).Replace(entry)
+}
+
+// unescapeEntry decodes a line written by escapeEntry.
+// Unknown escapes are kept as written.
+func unescapeEntry(line string) string {
+	if !strings.Contains(line, `\`) {
+		return line
+	}
+
+	var b strings.Builder
+	for idx := 0; idx < len(line); idx++ {
+		if line[idx] != '\\' || idx+1 == len(line) {
+			b.WriteByte(line[idx])
+			continue
+		}
+
+		idx++
+		switch line[idx] {
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case '\\':
+			b.WriteByte('\\')
+		default:
+			b.WriteByte('\\')
+			b.WriteByte(line[idx])
+		}
+	}
+	return b.String()
+}
@@ -1,9 +1,15 @@
 package ghostline
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHistory(t *testing.T) {
@@ -200,3 +206,184 @@ func TestHistoryNavigation(t *testing.T) {
 		assert.Equal(t, "typed", entry)
 	})
 }
+
+func TestHistoryOptions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("WithMaxEntries drops oldest entries", func(t *testing.T) {
+		t.Parallel()
+
+		h := NewHistory(WithMaxEntries(2))
+
+		h.Add("first")
+		h.Add("second")
+		h.Add("third")
+
+		assert.Equal(t, []string{"second", "third"}, h.Entries())
+	})
+
+	t.Run("DedupNone keeps consecutive duplicates", func(t *testing.T) {
+		t.Parallel()
+
+		h := NewHistory(WithDedup(DedupNone))
+
+		h.Add("same")
+		h.Add("same")
+
+		assert.Equal(t, 2, h.Len())
+	})
+
+	t.Run("DedupAll keeps only the newest copy", func(t *testing.T) {
+		t.Parallel()
+
+		h := NewHistory(WithDedup(DedupAll))
+
+		h.Add("first")
+		h.Add("second")
+		h.Add("first")
+
+		assert.Equal(t, []string{"second", "first"}, h.Entries())
+		entry, _ := h.Previous("")
+		assert.Equal(t, "first", entry)
+	})
+}
+
+func TestHistoryFile(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Save and Load round trip multiline entries", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "history")
+		h := NewHistory()
+		h.Add("select *\nfrom users")
+		h.Add(`echo "a\nb" \`)
+
+		err := h.Save(path)
+		require.NoError(t, err)
+		loaded := NewHistory()
+		err = loaded.Load(path)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"select *\nfrom users", `echo "a\nb" \`}, loaded.Entries())
+		data, _ := os.ReadFile(path)
+		assert.Equal(t, 2, strings.Count(string(data), "\n"))
+	})
+
+	t.Run("Load treats a missing file as empty", func(t *testing.T) {
+		t.Parallel()
+
+		h := NewHistory()
+		h.Add("stale")
+
+		err := h.Load(filepath.Join(t.TempDir(), "missing"))
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, h.Len())
+	})
+
+	t.Run("Load applies limits and dedup", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "history")
+		require.NoError(t, os.WriteFile(path, []byte("a\nb\na\nc\nd\n"), 0o600))
+		h := NewHistory(WithMaxEntries(3), WithDedup(DedupAll))
+
+		err := h.Load(path)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "c", "d"}, h.Entries())
+	})
+
+	t.Run("Save merges entries saved by others", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "history")
+		first := NewHistory()
+		second := NewHistory()
+		first.Add("one")
+		second.Add("two")
+
+		require.NoError(t, first.Save(path))
+		require.NoError(t, second.Save(path))
+		require.NoError(t, first.Save(path))
+		loaded := NewHistory()
+		require.NoError(t, loaded.Load(path))
+
+		assert.Equal(t, []string{"one", "two"}, loaded.Entries())
+	})
+
+	t.Run("OpenHistory appends on Add", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "history")
+		h, err := OpenHistory(path)
+		require.NoError(t, err)
+
+		h.Add("ls")
+		h.Add("cd /tmp\npwd")
+
+		require.NoError(t, h.Err())
+		data, _ := os.ReadFile(path)
+		assert.Equal(t, "ls\ncd /tmp\\npwd\n", string(data))
+	})
+
+	t.Run("OpenHistory compacts the file past twice the limit", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "history")
+		h, err := OpenHistory(path, WithMaxEntries(2))
+		require.NoError(t, err)
+
+		for _, entry := range []string{"a", "b", "c", "d", "e"} {
+			h.Add(entry)
+		}
+
+		require.NoError(t, h.Err())
+		data, _ := os.ReadFile(path)
+		assert.Equal(t, "d\ne\n", string(data))
+		assert.Equal(t, []string{"d", "e"}, h.Entries())
+	})
+
+	t.Run("OpenHistory records an append error", func(t *testing.T) {
+		t.Parallel()
+
+		dir := filepath.Join(t.TempDir(), "gone")
+		require.NoError(t, os.Mkdir(dir, 0o700))
+		h, err := OpenHistory(filepath.Join(dir, "history"))
+		require.NoError(t, err)
+		require.NoError(t, os.RemoveAll(dir))
+
+		h.Add("ls")
+
+		assert.Error(t, h.Err())
+		assert.Equal(t, 1, h.Len())
+	})
+
+	t.Run("concurrent writers keep every entry", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "history")
+		var wg sync.WaitGroup
+
+		for writer := range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				h, err := OpenHistory(path, WithDedup(DedupNone))
+				if !assert.NoError(t, err) {
+					return
+				}
+				for entry := range 25 {
+					h.Add(fmt.Sprintf("writer %d entry %d", writer, entry))
+				}
+				assert.NoError(t, h.Err())
+			}()
+		}
+		wg.Wait()
+
+		loaded := NewHistory(WithDedup(DedupNone))
+		require.NoError(t, loaded.Load(path))
+		assert.Equal(t, 100, loaded.Len())
+	})
+}
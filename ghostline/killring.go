@@ -0,0 +1,87 @@
+package ghostline
+
+import (
+	"bufio"
+	"slices"
+)
+
+// killRingSize caps the number of killed texts kept for yanking.
+const killRingSize = 32
+
+// command classifies the command run by a keystroke, so the next keystroke
+// can tell whether it continues it: consecutive kills build up one kill ring
+// entry, yank-pop only follows a yank, and consecutive typed characters
+// form one undo step.
+type command int
+
+const (
+	commandOther  command = iota // any other command, or none
+	commandInsert                // typed a character
+	commandKill                  // killed text (Ctrl+K, Ctrl+U, Ctrl+W)
+	commandYank                  // inserted killed text (Ctrl+Y, Alt+Y)
+)
+
+// kill saves text removed by a kill command in the kill ring. When the
+// previous keystroke was a kill too, text joins the newest entry instead:
+// appended for forward kills, prepended for backward ones, so the combined
+// text reads as it did in the buffer.
+func (i *Input) kill(text []rune, backward bool) {
+	i.thisCommand = commandKill
+	if len(text) == 0 {
+		return
+	}
+
+	if i.lastCommand == commandKill && len(i.killRing) > 0 {
+		newest := &i.killRing[len(i.killRing)-1]
+		if backward {
+			*newest = slices.Concat(text, *newest)
+		} else {
+			*newest = slices.Concat(*newest, text)
+		}
+		return
+	}
+
+	i.killRing = append(i.killRing, slices.Clone(text))
+	if len(i.killRing) > killRingSize {
+		i.killRing = slices.Delete(i.killRing, 0, len(i.killRing)-killRingSize)
+	}
+}
+
+// handleCtrlY inserts the most recently killed text at the cursor (yank).
+func handleCtrlY(i *Input, _ *bufio.Reader) (string, action) {
+	if len(i.killRing) == 0 {
+		return "", actionContinue
+	}
+
+	i.yankIndex = len(i.killRing) - 1
+	i.yankStart = i.cursorPos
+	i.insertYank()
+	return "", actionContinue
+}
+
+// handleAltY replaces the text inserted by the previous yank with the next
+// older kill ring entry, cycling back to the newest after the oldest
+// (yank-pop). Does nothing unless the previous keystroke yanked.
+func handleAltY(i *Input, _ *bufio.Reader) (string, action) {
+	if i.lastCommand != commandYank || len(i.killRing) == 0 {
+		return "", actionContinue
+	}
+
+	i.buffer = slices.Delete(i.buffer, i.yankStart, i.yankEnd)
+	i.cursorPos = i.yankStart
+	i.yankIndex = (i.yankIndex - 1 + len(i.killRing)) % len(i.killRing)
+	i.insertYank()
+	return "", actionContinue
+}
+
+// insertYank inserts the kill ring entry at yankIndex at the cursor,
+// recording where it went for a following yank-pop.
+func (i *Input) insertYank() {
+	text := i.killRing[i.yankIndex]
+	i.buffer = slices.Insert(i.buffer, i.cursorPos, text...)
+	i.cursorPos += len(text)
+	i.yankEnd = i.cursorPos
+	i.thisCommand = commandYank
+	i.matchIndex = 0
+	i.render()
+}
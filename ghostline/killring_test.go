@@ -0,0 +1,136 @@
+package ghostline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKillRing(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Ctrl+K then Ctrl+Y restores killed text", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("hello world", 5)
+		input.handlers = defaultHandlers()
+
+		pressKey(input, keyCtrlK, "")
+		assert.Equal(t, "hello", string(input.buffer))
+
+		pressKey(input, keyCtrlY, "")
+		assert.Equal(t, "hello world", string(input.buffer))
+		assert.Equal(t, 11, input.cursorPos)
+	})
+
+	t.Run("yank inserts at cursor", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("foo bar", 7)
+		input.handlers = defaultHandlers()
+
+		pressKey(input, keyCtrlW, "")
+		pressKey(input, keyCtrlA, "")
+		pressKey(input, keyCtrlY, "")
+
+		assert.Equal(t, "barfoo ", string(input.buffer))
+		assert.Equal(t, 3, input.cursorPos)
+	})
+
+	t.Run("consecutive backward kills prepend", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("one two three", 13)
+		input.handlers = defaultHandlers()
+
+		pressKey(input, keyCtrlW, "")
+		pressKey(input, keyCtrlW, "")
+
+		assert.Equal(t, [][]rune{[]rune("two three")}, input.killRing)
+	})
+
+	t.Run("consecutive forward kills append", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("", 0)
+
+		input.kill([]rune("one"), false)
+		input.lastCommand = input.thisCommand
+		input.kill([]rune(" two"), false)
+
+		assert.Equal(t, [][]rune{[]rune("one two")}, input.killRing)
+	})
+
+	t.Run("other keys start a new entry", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("one two", 7)
+		input.handlers = defaultHandlers()
+
+		pressKey(input, keyCtrlW, "")
+		pressKeys(input, "x")
+		pressKey(input, keyCtrlU, "")
+
+		assert.Equal(t, [][]rune{[]rune("two"), []rune("one x")}, input.killRing)
+	})
+
+	t.Run("Alt+Y cycles through older kills", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("", 0)
+		input.handlers = defaultHandlers()
+		input.killRing = [][]rune{[]rune("first"), []rune("second"), []rune("third")}
+
+		pressKey(input, keyCtrlY, "")
+		assert.Equal(t, "third", string(input.buffer))
+
+		pressKey(input, keyEscape, "y")
+		assert.Equal(t, "second", string(input.buffer))
+
+		pressKey(input, keyEscape, "y")
+		pressKey(input, keyEscape, "y")
+		assert.Equal(t, "third", string(input.buffer))
+		assert.Equal(t, 5, input.cursorPos)
+	})
+
+	t.Run("Alt+Y replaces yank in the middle of the buffer", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("<>", 1)
+		input.handlers = defaultHandlers()
+		input.killRing = [][]rune{[]rune("a"), []rune("bb")}
+
+		pressKey(input, keyCtrlY, "")
+		pressKey(input, keyEscape, "y")
+
+		assert.Equal(t, "<a>", string(input.buffer))
+		assert.Equal(t, 2, input.cursorPos)
+	})
+
+	t.Run("Alt+Y does nothing after other keys", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("", 0)
+		input.handlers = defaultHandlers()
+		input.killRing = [][]rune{[]rune("a"), []rune("b")}
+
+		pressKey(input, keyCtrlY, "")
+		pressKeys(input, "x")
+		pressKey(input, keyEscape, "y")
+
+		assert.Equal(t, "bx", string(input.buffer))
+	})
+
+	t.Run("ring keeps newest entries", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("", 0)
+		for n := range killRingSize + 2 {
+			input.lastCommand = commandOther
+			input.kill([]rune{rune('a' + n)}, false)
+		}
+
+		assert.Len(t, input.killRing, killRingSize)
+		assert.Equal(t, []rune{'c'}, input.killRing[0])
+	})
+}
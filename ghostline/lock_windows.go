@@ -0,0 +1,31 @@
+//go:build windows
+
+package ghostline
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockHistory takes an exclusive lock guarding the history file at path and
+// returns the function that releases it. The lock is held on a separate file
+// because the history file itself is replaced when rewritten.
+func lockHistory(path string) (func(), error) {
+	f, err := os.OpenFile(path+".lock", os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	handle := windows.Handle(f.Fd())
+	overlapped := new(windows.Overlapped)
+	if err := windows.LockFileEx(handle, windows.LOCKFILE_EXCLUSIVE_LOCK, 0, 1, 0, overlapped); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	return func() {
+		_ = windows.UnlockFileEx(handle, 0, 1, 0, overlapped)
+		_ = f.Close()
+	}, nil
+}
@@ -1,6 +1,9 @@
 package ghostline
 
-import "strings"
+import (
+	"slices"
+	"unicode"
+)
 
 // fuzzyScore calculates how well pattern matches text using fuzzy matching.
 // Returns a positive score for matches (higher is better) or -1 for no match.
@@ -23,8 +26,8 @@ func fuzzyScore(pattern, text string) int {
 		return 0
 	}
 
-	pRunes := []rune(strings.ToLower(pattern))
-	tRunes := []rune(strings.ToLower(text))
+	pRunes := lowerRunes(pattern)
+	tRunes := lowerRunes(text)
 
 	score, pIdx, prevMatchIdx, consecutive := 0, 0, -1, 0
 
@@ -76,3 +79,54 @@ func calculateMatchBonus(tIdx, prevMatchIdx, consecutive int, tRunes []rune) int
 
 	return bonus
 }
+
+// fuzzyPositions returns the indices of the runes in text matched by pattern,
+// ignoring case. A contiguous occurrence of pattern is preferred; otherwise the
+// runes are matched left to right as in fuzzyScore.
+// Returns nil if pattern is empty or does not match.
+//
+// Example:
+//
+//	fuzzyPositions("gc", "git-checkout")  // returns [0 4]
+//	fuzzyPositions("la", "ls -la")        // returns [4 5]
+func fuzzyPositions(pattern, text string) []int {
+	pRunes := lowerRunes(pattern)
+	tRunes := lowerRunes(text)
+	if len(pRunes) == 0 || len(pRunes) > len(tRunes) {
+		return nil
+	}
+
+	positions := make([]int, 0, len(pRunes))
+
+	// Contiguous occurrence
+	for start := 0; start+len(pRunes) <= len(tRunes); start++ {
+		if slices.Equal(tRunes[start:start+len(pRunes)], pRunes) {
+			for idx := range pRunes {
+				positions = append(positions, start+idx)
+			}
+			return positions
+		}
+	}
+
+	// Scattered match
+	for tIdx, char := range tRunes {
+		if char == pRunes[len(positions)] {
+			positions = append(positions, tIdx)
+			if len(positions) == len(pRunes) {
+				return positions
+			}
+		}
+	}
+	return nil
+}
+
+// lowerRunes returns the runes of s folded to lower case one at a time, so the
+// indices line up with []rune(s). [strings.ToLower] may change the rune count,
+// as for 'İ', which lowercases to two runes.
+func lowerRunes(s string) []rune {
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
@@ -50,3 +50,31 @@ func TestFuzzyScore(t *testing.T) {
 		assert.Greater(t, withSep, noSep)
 	})
 }
+
+func TestFuzzyPositions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		pattern string
+		text    string
+		want    []int
+	}{
+		{"prefix", "git", "git commit", []int{0, 1, 2}},
+		{"sparse", "gc", "git-checkout", []int{0, 4}},
+		{"prefers contiguous", "la", "ls -la", []int{4, 5}},
+		{"case insensitive", "GC", "git-checkout", []int{0, 4}},
+		{"multibyte", "éb", "café bar", []int{3, 5}},
+		{"lowercase changes rune count", "x", "İx", []int{1}},
+		{"dotted capital I", "ix", "İx", []int{0, 1}},
+		{"no match", "xyz", "hello", nil},
+		{"empty pattern", "", "hello", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, fuzzyPositions(tt.pattern, tt.text))
+		})
+	}
+}
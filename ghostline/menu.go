@@ -0,0 +1,129 @@
+package ghostline
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+	"golang.org/x/term"
+)
+
+// menuMaxRows is the number of candidates shown at once; longer menus scroll.
+const menuMaxRows = 8
+
+// renderMenu draws the completion menu below the current line, one row per
+// candidate, when 2 or more candidates exist. The selected candidate is
+// highlighted and descriptions appear in a dimmed column. When there are more
+// than menuMaxRows candidates, the menu scrolls to keep the selection visible
+// and a footer shows the position: [current/total].
+//
+// Rows are drawn after the cursor's line; render's screen clear removes them
+// on the next redraw. Returns the number of rows written.
+func (i *Input) renderMenu() int {
+	candidates := i.getCandidates()
+	if len(candidates) < 2 {
+		i.menuOffset = 0
+		return 0
+	}
+
+	selected := i.matchIndex % len(candidates)
+	rows := min(len(candidates), menuMaxRows)
+	i.scrollMenu(selected, rows, len(candidates))
+	visible := candidates[i.menuOffset : i.menuOffset+rows]
+
+	labelWidth := 0
+	for _, c := range visible {
+		labelWidth = max(labelWidth, runewidth.StringWidth(c.label()))
+	}
+
+	// Leave the last column free so rows never wrap
+	width := i.termWidth() - 1
+	indent := i.menuIndent(candidates[selected])
+	if width > 0 && indent > width/2 {
+		indent = 0
+	}
+	pad := strings.Repeat(" ", indent)
+
+	for idx, c := range visible {
+		label := " " + runewidth.FillRight(c.label(), labelWidth) + " "
+		desc := ""
+		if c.Description != "" {
+			desc = " " + c.Description + " "
+		}
+		if width > 0 {
+			label = runewidth.Truncate(label, width-indent, "…")
+			desc = runewidth.Truncate(desc, max(width-indent-runewidth.StringWidth(label), 0), "…")
+		}
+
+		_, _ = fmt.Fprint(i.out, "\n\r"+pad)
+		if i.menuOffset+idx == selected {
+			// Selected row (background: #374151)
+			_, _ = fmt.Fprintf(i.out, "\033[48;2;55;65;81m%s\033[38;2;156;163;175m%s\033[0m", label, desc)
+		} else {
+			// Description column (color: #4b5563)
+			_, _ = fmt.Fprintf(i.out, "%s\033[38;2;75;85;99m%s\033[0m", label, desc)
+		}
+	}
+
+	if len(candidates) == rows {
+		return rows
+	}
+
+	// Scroll position footer
+	_, _ = fmt.Fprintf(i.out, "\n\r%s\033[38;2;75;85;99m [%d/%d]\033[0m",
+		pad, i.currentMatchIndex(), i.countMatches())
+	return rows + 1
+}
+
+// scrollMenu adjusts the first visible row so the selected candidate is
+// within the rows shown out of total.
+func (i *Input) scrollMenu(selected, rows, total int) {
+	if selected < i.menuOffset {
+		i.menuOffset = selected
+	}
+	if selected >= i.menuOffset+rows {
+		i.menuOffset = selected - rows + 1
+	}
+	i.menuOffset = max(min(i.menuOffset, total-rows), 0)
+}
+
+// menuIndent returns the screen column where the menu starts: below the
+// start of the text the candidate replaces when that is on the last line,
+// otherwise below the start of the text after the prompt.
+func (i *Input) menuIndent(c Candidate) int {
+	lineStart := i.findLineStartFrom(len(i.buffer))
+	prompt := i.firstPrompt()
+	if lineStart > 0 {
+		prompt = i.contPrompt
+	}
+
+	indent := visibleWidth(prompt)
+	if c.Start >= lineStart {
+		indent += runewidth.StringWidth(string(i.buffer[lineStart:c.Start]))
+	}
+	return indent
+}
+
+// termWidth returns the terminal width in columns, or 0 if unknown.
+func (i *Input) termWidth() int {
+	width, _, err := term.GetSize(i.fd)
+	if err != nil {
+		return 0
+	}
+	return width
+}
+
+// countMatches returns how many suggestions match the current input.
+func (i *Input) countMatches() int {
+	return len(i.getMatches())
+}
+
+// currentMatchIndex returns the 1-based position of the selected match.
+// Returns 0 if no matches exist.
+func (i *Input) currentMatchIndex() int {
+	matches := i.getMatches()
+	if len(matches) == 0 {
+		return 0
+	}
+	return (i.matchIndex % len(matches)) + 1
+}
@@ -2,6 +2,7 @@ package ghostline
 
 import (
 	"bytes"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -121,155 +122,139 @@ func TestCurrentMatchIndex(t *testing.T) {
 	})
 }
 
-func TestGetPrevNextMatches(t *testing.T) {
+func TestRenderMenu(t *testing.T) {
 	t.Parallel()
 
-	t.Run("returns prev and next matches", func(t *testing.T) {
+	t.Run("renders a row per match", func(t *testing.T) {
 		t.Parallel()
 
 		// Order by score: help, hero, hello
+		var buf bytes.Buffer
 		input := &Input{
+			out:         &buf,
+			fd:          -1,
+			prompt:      "> ",
 			buffer:      []rune("he"),
 			suggestions: []string{"hello", "help", "hero"},
 			matchIndex:  1, // current is "hero"
 		}
 
-		prev, next := input.getPrevNextMatches()
+		rows := input.renderMenu()
 
-		assert.Equal(t, "help", prev)
-		assert.Equal(t, "hello", next)
+		assert.Equal(t, 3, rows)
+		assert.Equal(t, "\n\r   help  \033[38;2;75;85;99m\033[0m"+
+			"\n\r  \033[48;2;55;65;81m hero  \033[38;2;156;163;175m\033[0m"+
+			"\n\r   hello \033[38;2;75;85;99m\033[0m", buf.String())
 	})
 
-	t.Run("wraps around at start", func(t *testing.T) {
+	t.Run("aligns menu with the completed word", func(t *testing.T) {
 		t.Parallel()
 
-		// Order by score: help, hero, hello
-		input := &Input{
-			buffer:      []rune("he"),
-			suggestions: []string{"hello", "help", "hero"},
-			matchIndex:  0, // current is "help"
-		}
+		input := newTestInput("a\ngit co", 8)
+		input.prompt, input.contPrompt = ">>> ", "... "
 
-		prev, next := input.getPrevNextMatches()
+		indent := input.menuIndent(Candidate{Text: "commit", Start: 6, End: 8})
 
-		assert.Equal(t, "hello", prev) // wraps to last
-		assert.Equal(t, "hero", next)
+		assert.Equal(t, 8, indent) // "... " + "git "
 	})
 
-	t.Run("wraps around at end", func(t *testing.T) {
+	t.Run("renders descriptions in a column", func(t *testing.T) {
 		t.Parallel()
 
-		// Order by score: help, hero, hello
-		input := &Input{
-			buffer:      []rune("he"),
-			suggestions: []string{"hello", "help", "hero"},
-			matchIndex:  2, // current is "hello"
-		}
+		input := newTestInput("push --", 7)
+		input.SetCompleter(flagCompleter)
+		out := input.out.(*bytes.Buffer)
 
-		prev, next := input.getPrevNextMatches()
+		input.renderMenu()
 
-		assert.Equal(t, "hero", prev)
-		assert.Equal(t, "help", next) // wraps to first
+		assert.Contains(t, out.String(), "\033[48;2;55;65;81m --force ")
+		assert.Contains(t, out.String(), "\033[38;2;156;163;175m overwrite remote \033[0m")
+		assert.Contains(t, out.String(), " --tags  \033[38;2;75;85;99m push all tags \033[0m")
 	})
 
-	t.Run("returns empty for single match", func(t *testing.T) {
+	t.Run("does not render for single match", func(t *testing.T) {
 		t.Parallel()
 
+		var buf bytes.Buffer
 		input := &Input{
+			out:         &buf,
+			fd:          -1,
 			buffer:      []rune("wor"),
 			suggestions: []string{"hello", "world"},
 			matchIndex:  0,
 		}
 
-		prev, next := input.getPrevNextMatches()
+		rows := input.renderMenu()
 
-		assert.Empty(t, prev)
-		assert.Empty(t, next)
+		assert.Zero(t, rows)
+		assert.Empty(t, buf.String())
 	})
 
-	t.Run("returns empty for no matches", func(t *testing.T) {
+	t.Run("does not render for no matches", func(t *testing.T) {
 		t.Parallel()
 
+		var buf bytes.Buffer
 		input := &Input{
+			out:         &buf,
+			fd:          -1,
 			buffer:      []rune("xyz"),
 			suggestions: []string{"hello"},
 		}
 
-		prev, next := input.getPrevNextMatches()
+		rows := input.renderMenu()
 
-		assert.Empty(t, prev)
-		assert.Empty(t, next)
+		assert.Zero(t, rows)
+		assert.Empty(t, buf.String())
 	})
-}
-
-func TestRenderDropdown(t *testing.T) {
-	t.Parallel()
 
-	t.Run("renders dropdown with multiple matches", func(t *testing.T) {
+	t.Run("scrolls to keep the selection visible", func(t *testing.T) {
 		t.Parallel()
 
-		// Order by score: help, hero, hello
-		var buf bytes.Buffer
-		input := &Input{
-			out:         &buf,
-			buffer:      []rune("he"),
-			suggestions: []string{"hello", "help", "hero"},
-			matchIndex:  0, // current is "help"
-		}
-
-		input.renderDropdown()
+		input := newTestInput("item", 4)
+		input.suggestions = []string{"item0", "item1", "item2", "item3", "item4",
+			"item5", "item6", "item7", "item8", "item9"}
+		input.matchIndex = 9
+		out := input.out.(*bytes.Buffer)
 
-		output := buf.String()
-		assert.Contains(t, output, "[1/3")
-		assert.Contains(t, output, "hello") // prev (wraps to last)
-		assert.Contains(t, output, "hero")  // next
-	})
+		rows := input.renderMenu()
 
-	t.Run("does not render for single match", func(t *testing.T) {
-		t.Parallel()
-
-		var buf bytes.Buffer
-		input := &Input{
-			out:         &buf,
-			buffer:      []rune("wor"),
-			suggestions: []string{"hello", "world"},
-			matchIndex:  0,
-		}
+		assert.Equal(t, menuMaxRows+1, rows)
+		assert.Equal(t, 2, input.menuOffset)
+		assert.NotContains(t, out.String(), "item1")
+		assert.Contains(t, out.String(), "item2")
+		assert.Contains(t, out.String(), "[10/10]")
 
-		input.renderDropdown()
+		input.matchIndex = 0
+		input.renderMenu()
 
-		assert.Empty(t, buf.String())
+		assert.Equal(t, 0, input.menuOffset)
 	})
 
-	t.Run("does not render for no matches", func(t *testing.T) {
+	t.Run("render moves the cursor back above the menu", func(t *testing.T) {
 		t.Parallel()
 
-		var buf bytes.Buffer
-		input := &Input{
-			out:         &buf,
-			buffer:      []rune("xyz"),
-			suggestions: []string{"hello"},
-		}
+		input := newTestInput("he", 2)
+		input.suggestions = []string{"hello", "help", "hero"}
+		input.prompt = "> "
+		out := input.out.(*bytes.Buffer)
 
-		input.renderDropdown()
+		input.render()
 
-		assert.Empty(t, buf.String())
+		assert.True(t, strings.HasSuffix(out.String(), "\033[3A\r\033[5G"))
+		assert.Equal(t, 1, input.prevLines)
 	})
 
-	t.Run("updates with matchIndex changes", func(t *testing.T) {
+	t.Run("renderFinal clears the menu", func(t *testing.T) {
 		t.Parallel()
 
-		var buf bytes.Buffer
-		input := &Input{
-			out:         &buf,
-			buffer:      []rune("he"),
-			suggestions: []string{"hello", "help"},
-			matchIndex:  1,
-		}
+		input := newTestInput("he", 1)
+		input.suggestions = []string{"hello", "help", "hero"}
+		input.prompt = "> "
+		out := input.out.(*bytes.Buffer)
 
-		input.renderDropdown()
+		input.renderFinal()
 
-		output := buf.String()
-		assert.Contains(t, output, "[2/2")
+		assert.Equal(t, "\r\033[J> he\r\033[5G", out.String())
+		assert.Equal(t, 2, input.cursorPos)
 	})
 }
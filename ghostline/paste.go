@@ -0,0 +1,73 @@
+package ghostline
+
+import (
+	"bufio"
+	"slices"
+	"unicode"
+)
+
+// pasteEnd is the sequence the terminal sends after bracketed paste content.
+const pasteEnd = "\033[201~"
+
+// handlePaste processes a bracketed paste, which starts with ESC [ 200 ~.
+// Reads the pasted text up to ESC [ 201 ~ and inserts it at the cursor as one
+// edit: line breaks become newlines in the buffer instead of submitting the
+// input, and the input is redrawn once rather than per character.
+func handlePaste(i *Input, reader *bufio.Reader) {
+	for _, want := range []byte("00~") {
+		b, err := reader.ReadByte()
+		if err != nil || b != want {
+			return
+		}
+	}
+
+	text, err := readPaste(reader)
+	if err != nil && len(text) == 0 {
+		return
+	}
+	i.insertPaste(text)
+}
+
+// readPaste reads bracketed paste content up to and excluding pasteEnd.
+// On a read error it returns the text read so far with the error.
+func readPaste(reader *bufio.Reader) ([]rune, error) {
+	end := []rune(pasteEnd)
+	var text []rune
+	for {
+		r, _, err := reader.ReadRune()
+		if err != nil {
+			return text, err
+		}
+
+		text = append(text, r)
+		if len(text) >= len(end) && slices.Equal(text[len(text)-len(end):], end) {
+			return text[:len(text)-len(end)], nil
+		}
+	}
+}
+
+// insertPaste inserts pasted text at the cursor. Line breaks (\r\n or \r, as
+// terminals send Enter) become \n; other control characters are dropped so
+// they cannot act as keystrokes or corrupt the display.
+func (i *Input) insertPaste(text []rune) {
+	clean := make([]rune, 0, len(text))
+	for idx, r := range text {
+		switch {
+		case r == '\r':
+			if idx+1 < len(text) && text[idx+1] == '\n' {
+				continue
+			}
+			clean = append(clean, '\n')
+		case r == '\n' || r == '\t' || unicode.IsPrint(r):
+			clean = append(clean, r)
+		}
+	}
+	if len(clean) == 0 {
+		return
+	}
+
+	i.buffer = slices.Insert(i.buffer, i.cursorPos, clean...)
+	i.cursorPos += len(clean)
+	i.matchIndex = 0
+	i.render()
+}
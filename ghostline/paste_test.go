@@ -0,0 +1,99 @@
+package ghostline
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBracketedPaste(t *testing.T) {
+	t.Parallel()
+
+	t.Run("inserts multiline paste without submitting", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("", 0)
+		input.handlers = defaultHandlers()
+
+		result, act := input.handleKey(keyEscape, bufio.NewReader(strings.NewReader("[200~select *\rfrom t;\r\n\033[201~")))
+
+		assert.Equal(t, actionContinue, act)
+		assert.Empty(t, result)
+		assert.Equal(t, "select *\nfrom t;\n", string(input.buffer))
+		assert.Equal(t, 17, input.cursorPos)
+	})
+
+	t.Run("inserts at cursor", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("echo ", 0)
+		input.handlers = defaultHandlers()
+
+		pressKey(input, keyEscape, "[200~sudo \033[201~")
+
+		assert.Equal(t, "sudo echo ", string(input.buffer))
+		assert.Equal(t, 5, input.cursorPos)
+	})
+
+	t.Run("drops control characters", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("", 0)
+		input.handlers = defaultHandlers()
+
+		pressKey(input, keyEscape, "[200~a\x03\tb\x1b[A\033[201~")
+
+		assert.Equal(t, "a\tb[A", string(input.buffer))
+	})
+
+	t.Run("renders once", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("", 0)
+		input.handlers = defaultHandlers()
+
+		pressKey(input, keyEscape, "[200~"+strings.Repeat("x", 100)+"\033[201~")
+
+		assert.Equal(t, 1, strings.Count(input.out.(*bytes.Buffer).String(), "\033[J"))
+	})
+
+	t.Run("is one undo step", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("", 0)
+		input.handlers = defaultHandlers()
+
+		pressKeys(input, "ls ")
+		pressKey(input, keyEscape, "[200~one\rtwo\033[201~")
+		pressKey(input, keyCtrlUnder, "")
+
+		assert.Equal(t, "ls ", string(input.buffer))
+	})
+
+	t.Run("keeps text of unterminated paste", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("", 0)
+		input.handlers = defaultHandlers()
+
+		pressKey(input, keyEscape, "[200~partial")
+
+		assert.Equal(t, "partial", string(input.buffer))
+	})
+
+	t.Run("pastes in vi insert mode", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("", 0)
+		input.handlers = defaultHandlers()
+		input.SetEditMode(ViMode)
+
+		pressKey(input, keyEscape, "[200~a\rb\033[201~")
+
+		assert.Equal(t, "a\nb", string(input.buffer))
+		assert.False(t, input.vi.normal)
+	})
+}
@@ -18,60 +18,94 @@ func visibleWidth(s string) int {
 
 // render redraws the entire input area including prompt, text, and ghost suggestions.
 // Clears previous output, displays each line with appropriate prompts, and positions
-// the cursor correctly for multiline editing.
+// the cursor correctly for multiline editing. Styling from the Highlighter does not
+// affect cursor placement, which is computed from the display width of the buffer.
 //
 // Uses ANSI escape sequences for terminal control:
 //   - ESC[nA: move cursor up n lines
 //   - ESC[J:  clear from cursor to end of screen
 //   - ESC[nG: move cursor to column n
-//   - ESC[38;2;r;g;bm: set 24-bit foreground color
+//   - ESC[38;2;r;g;bm: set 24-bit foreground color (see Style for the full set)
 func (i *Input) render() {
-	// Move cursor up to first line if we had multiple lines before
+	i.draw(true)
+}
+
+// renderFinal redraws the input without ghost text or completion menu and
+// leaves the cursor at the end of the buffer, so output following a submitted
+// line starts below it.
+func (i *Input) renderFinal() {
+	i.cursorPos = len(i.buffer)
+	i.draw(false)
+}
+
+// draw implements render. Hints (ghost text and the completion menu) are
+// only drawn when hints is set and the cursor is at the end of the buffer.
+func (i *Input) draw(hints bool) {
+	// Move cursor up to first line from the line it was left on
 	if i.prevLines > 1 {
 		_, _ = fmt.Fprintf(i.out, "\033[%dA", i.prevLines-1)
 	}
 
-	// Clear from cursor to end of screen
+	// Clear from cursor to end of screen (including any menu rows)
 	_, _ = fmt.Fprintf(i.out, "\r\033[J")
 
 	// Split buffer into lines
 	lines := strings.Split(string(i.buffer), "\n")
-	i.prevLines = len(lines)
 
 	// Find cursor line and column
 	cursorLine, cursorCol := i.getCursorPosition()
+	i.prevLines = cursorLine + 1
+
+	firstPrompt := i.firstPrompt()
 
-	// Render each line
+	// Render each line, styled by the highlighter or history search
+	styles := i.bufferStyles()
+	offset := 0
 	for idx, line := range lines {
 		if idx > 0 {
 			_, _ = fmt.Fprint(i.out, "\n\r") // \n moves down, \r goes to column 0
 		}
+
+		runes := []rune(line)
+		text := string(runes)
+		if styles != nil {
+			text = styleLine(runes, styles[offset:offset+len(runes)])
+		}
+		offset += len(runes) + 1
+
 		if idx == 0 {
-			_, _ = fmt.Fprintf(i.out, "%s%s", i.prompt, line)
+			_, _ = fmt.Fprintf(i.out, "%s%s", firstPrompt, text)
 		} else {
-			_, _ = fmt.Fprintf(i.out, "%s%s", i.contPrompt, line)
+			_, _ = fmt.Fprintf(i.out, "%s%s", i.contPrompt, text)
 		}
 	}
 
-	// Show ghost text and dropdown (only when cursor is at end of last line)
-	if i.cursorPos == len(i.buffer) {
-		// Ghost text (color: #6b7280)
-		if ghost := i.findGhost(); ghost != "" {
-			_, _ = fmt.Fprintf(i.out, "\033[38;2;107;114;128m%s\033[0m", ghost)
+	// Show ghost text (only when cursor is at end of last line) and the
+	// completion menu, with any validation error between the input and the menu
+	extraRows := 0
+	if hints {
+		completing := i.search == nil && i.completing()
+		if completing && i.cursorPos == len(i.buffer) {
+			// Ghost text (color: #6b7280)
+			if ghost := i.findGhost(); ghost != "" {
+				_, _ = fmt.Fprintf(i.out, "\033[38;2;107;114;128m%s\033[0m", ghost)
+			}
+		}
+		extraRows += i.renderValidation()
+		if completing {
+			extraRows += i.renderMenu()
 		}
-		// Dropdown hints
-		i.renderDropdown()
 	}
 
 	// Position cursor correctly
-	// Move to the cursor line (from the last line)
-	linesFromEnd := len(lines) - 1 - cursorLine
+	// Move to the cursor line (from the last line or row below the input)
+	linesFromEnd := len(lines) - 1 - cursorLine + extraRows
 	if linesFromEnd > 0 {
 		_, _ = fmt.Fprintf(i.out, "\033[%dA", linesFromEnd)
 	}
 
 	// Move to correct column using absolute positioning
-	prompt := i.prompt
+	prompt := firstPrompt
 	if cursorLine > 0 {
 		prompt = i.contPrompt
 	}
@@ -79,6 +113,16 @@ func (i *Input) render() {
 	_, _ = fmt.Fprintf(i.out, "\r\033[%dG", col+1) // \r to start, \033[nG is 1-indexed
 }
 
+// firstPrompt returns the prompt drawn before the first input line. During a
+// history search the search prompt replaces the normal one; in vi mode the
+// prompt is preceded by the mode indicator.
+func (i *Input) firstPrompt() string {
+	if i.search != nil {
+		return i.search.prompt()
+	}
+	return i.modeIndicator() + i.prompt
+}
+
 // getCursorPosition returns the cursor's line and column within the buffer.
 // Line is 0-indexed. Column is measured in display width (handles wide characters).
 func (i *Input) getCursorPosition() (line, col int) {
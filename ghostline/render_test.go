@@ -1,6 +1,8 @@
 package ghostline
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -34,3 +36,22 @@ func TestVisibleWidth(t *testing.T) {
 		})
 	}
 }
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	t.Run("moves up from the cursor line before redrawing", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("one\ntwo\nthree", 5) // cursor on second line
+		input.prompt, input.contPrompt = "> ", ". "
+		out := input.out.(*bytes.Buffer)
+
+		input.render()
+		out.Reset()
+		input.render()
+
+		assert.Equal(t, 2, input.prevLines)
+		assert.True(t, strings.HasPrefix(out.String(), "\033[1A\r\033[J> one"))
+	})
+}
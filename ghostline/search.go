@@ -0,0 +1,167 @@
+package ghostline
+
+import (
+	"bufio"
+	"slices"
+	"unicode"
+)
+
+// historySearch holds the state of an incremental history search (Ctrl+R/Ctrl+S).
+// While a search is active the buffer shows the matched entry, and the original
+// input is kept for restoring when the search is aborted.
+type historySearch struct {
+	query     []rune // text typed since the search started
+	reverse   bool   // true when searching toward older entries
+	index     int    // history index of the current match (-1 = none yet)
+	positions []int  // buffer indices of the matched runes, for highlighting
+	failed    bool   // true when the last query extension found no match
+
+	original       []rune // buffer before the search started
+	originalCursor int    // cursor position before the search started
+}
+
+// prompt returns the bash-style search prompt, e.g. "(reverse-i-search)`git': ".
+func (s *historySearch) prompt() string {
+	label := "i-search"
+	if s.reverse {
+		label = "reverse-i-search"
+	}
+	if s.failed {
+		label = "failed " + label
+	}
+	return "(" + label + ")`" + string(s.query) + "': "
+}
+
+// handleCtrlR starts a reverse incremental history search.
+func handleCtrlR(i *Input, _ *bufio.Reader) (string, action) {
+	i.startSearch(true)
+	return "", actionContinue
+}
+
+// handleCtrlS starts a forward incremental history search.
+func handleCtrlS(i *Input, _ *bufio.Reader) (string, action) {
+	i.startSearch(false)
+	return "", actionContinue
+}
+
+// startSearch enters search mode, saving the current input for abort.
+func (i *Input) startSearch(reverse bool) {
+	i.search = &historySearch{
+		reverse:        reverse,
+		index:          -1,
+		original:       slices.Clone(i.buffer),
+		originalCursor: i.cursorPos,
+	}
+	i.render()
+}
+
+// handleSearchKey processes a keystroke while a search is active.
+// Returns true if the key was consumed by the search. Any other key accepts
+// the current match and returns false, so it is then handled as usual
+// (e.g. Enter submits the match, arrow keys start editing it).
+//
+// Keys consumed by the search:
+//   - printable characters: extend the query
+//   - Backspace: shorten the query
+//   - Ctrl+R / Ctrl+S: find the next older / newer match
+//   - Ctrl+G: abort and restore the original input
+func (i *Input) handleSearchKey(r rune) bool {
+	s := i.search
+
+	switch {
+	case r == keyCtrlR || r == keyCtrlS:
+		s.reverse = r == keyCtrlR
+		if s.index >= 0 {
+			i.findSearchMatch(s.index, true)
+		} else {
+			i.findSearchMatch(i.searchStart(), false)
+		}
+	case r == keyCtrlG:
+		i.buffer = s.original
+		i.cursorPos = s.originalCursor
+		i.search = nil
+	case r == keyBackspace || r == keyDelete:
+		if len(s.query) == 0 {
+			return true
+		}
+		s.query = s.query[:len(s.query)-1]
+		i.findSearchMatch(i.searchStart(), false)
+	case unicode.IsPrint(r):
+		s.query = append(s.query, r)
+		start := s.index
+		if start < 0 {
+			start = i.searchStart()
+		}
+		i.findSearchMatch(start, false)
+	default:
+		i.acceptSearch()
+		return false
+	}
+
+	i.matchIndex = 0
+	i.render()
+	return true
+}
+
+// searchStart returns the history index a fresh search begins at:
+// the newest entry for reverse search, the oldest for forward search.
+func (i *Input) searchStart() int {
+	if i.search.reverse {
+		return len(i.history.entries) - 1
+	}
+	return 0
+}
+
+// findSearchMatch looks for the query in history starting at index from and
+// moving in the search direction. With skip set, the entry at from and entries
+// with the same text as the current match are passed over, so repeated
+// Ctrl+R presses move on to a different entry.
+// On failure the previous match stays displayed and the search is marked failed.
+func (i *Input) findSearchMatch(from int, skip bool) {
+	s := i.search
+	entries := i.history.entries
+
+	if len(s.query) == 0 {
+		s.index, s.positions, s.failed = -1, nil, false
+		i.buffer = slices.Clone(s.original)
+		i.cursorPos = s.originalCursor
+		return
+	}
+
+	step := 1
+	if s.reverse {
+		step = -1
+	}
+
+	current := string(i.buffer)
+	query := string(s.query)
+	for idx := from; idx >= 0 && idx < len(entries); idx += step {
+		if skip && (idx == from || entries[idx] == current) {
+			continue
+		}
+		if fuzzyScore(query, entries[idx]) < 0 {
+			continue
+		}
+
+		s.index, s.failed = idx, false
+		s.positions = fuzzyPositions(query, entries[idx])
+		i.buffer = []rune(entries[idx])
+		i.cursorPos = s.positions[0]
+		return
+	}
+
+	s.failed = true
+}
+
+// acceptSearch leaves search mode keeping the matched entry in the buffer,
+// with the cursor at its end. Undo then restores the input from before the search.
+func (i *Input) acceptSearch() {
+	if s := i.search; !slices.Equal(s.original, i.buffer) {
+		i.pushUndo(editState{buffer: s.original, cursor: s.originalCursor})
+		i.undoGroup = false
+	}
+	i.search = nil
+	i.cursorPos = len(i.buffer)
+	i.matchIndex = 0
+	i.render()
+}
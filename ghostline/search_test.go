@@ -0,0 +1,150 @@
+package ghostline
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSearchInput(buffer string, entries ...string) *Input {
+	input := newTestInput(buffer, len([]rune(buffer)))
+	for _, entry := range entries {
+		input.history.Add(entry)
+	}
+	return input
+}
+
+func typeSearch(input *Input, query string) {
+	for _, r := range query {
+		input.handleSearchKey(r)
+	}
+}
+
+func TestHistorySearch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("handleCtrlR starts reverse search", func(t *testing.T) {
+		t.Parallel()
+
+		input := newSearchInput("draft", "git status")
+
+		handleCtrlR(input, nil)
+
+		require.NotNil(t, input.search)
+		assert.True(t, input.search.reverse)
+		assert.Equal(t, "draft", string(input.buffer))
+		assert.Contains(t, input.out.(*bytes.Buffer).String(), "(reverse-i-search)`': draft")
+	})
+
+	t.Run("typing finds newest fuzzy match", func(t *testing.T) {
+		t.Parallel()
+
+		input := newSearchInput("", "git checkout main", "ls", "git commit", "pwd")
+		handleCtrlR(input, nil)
+
+		typeSearch(input, "gco")
+
+		assert.Equal(t, "git commit", string(input.buffer))
+		assert.Equal(t, []int{0, 4, 5}, input.search.positions)
+		assert.Equal(t, 0, input.cursorPos)
+	})
+
+	t.Run("Ctrl+R moves to older matches", func(t *testing.T) {
+		t.Parallel()
+
+		input := newSearchInput("", "git checkout main", "git commit", "ls", "git commit")
+		handleCtrlR(input, nil)
+		typeSearch(input, "gco")
+
+		input.handleSearchKey(keyCtrlR)
+
+		assert.Equal(t, "git checkout main", string(input.buffer))
+		assert.False(t, input.search.failed)
+	})
+
+	t.Run("no match keeps previous entry and fails", func(t *testing.T) {
+		t.Parallel()
+
+		input := newSearchInput("", "make test")
+		handleCtrlR(input, nil)
+		typeSearch(input, "mk")
+
+		typeSearch(input, "z")
+
+		assert.Equal(t, "make test", string(input.buffer))
+		assert.True(t, input.search.failed)
+		assert.Equal(t, "(failed reverse-i-search)`mkz': ", input.search.prompt())
+	})
+
+	t.Run("Backspace shortens the query", func(t *testing.T) {
+		t.Parallel()
+
+		input := newSearchInput("", "make test")
+		handleCtrlR(input, nil)
+		typeSearch(input, "mkz")
+
+		input.handleSearchKey(keyBackspace)
+
+		assert.Equal(t, "mk", string(input.search.query))
+		assert.False(t, input.search.failed)
+	})
+
+	t.Run("Ctrl+S searches forward", func(t *testing.T) {
+		t.Parallel()
+
+		input := newSearchInput("", "echo one", "echo two")
+		handleCtrlS(input, nil)
+
+		typeSearch(input, "echo")
+
+		assert.Equal(t, "echo one", string(input.buffer))
+		assert.Equal(t, "(i-search)`echo': ", input.search.prompt())
+	})
+
+	t.Run("Ctrl+G restores original input", func(t *testing.T) {
+		t.Parallel()
+
+		input := newSearchInput("draft", "git status")
+		input.cursorPos = 2
+		handleCtrlR(input, nil)
+		typeSearch(input, "git")
+
+		consumed := input.handleSearchKey(keyCtrlG)
+
+		assert.True(t, consumed)
+		assert.Nil(t, input.search)
+		assert.Equal(t, "draft", string(input.buffer))
+		assert.Equal(t, 2, input.cursorPos)
+	})
+
+	t.Run("other keys accept the match", func(t *testing.T) {
+		t.Parallel()
+
+		input := newSearchInput("", "git status")
+		handleCtrlR(input, nil)
+		typeSearch(input, "st")
+
+		consumed := input.handleSearchKey(keyEnter)
+
+		assert.False(t, consumed)
+		assert.Nil(t, input.search)
+		assert.Equal(t, "git status", string(input.buffer))
+		assert.Equal(t, len(input.buffer), input.cursorPos)
+	})
+
+	t.Run("render highlights matched runes", func(t *testing.T) {
+		t.Parallel()
+
+		input := newSearchInput("", "ls -la")
+		handleCtrlR(input, nil)
+		typeSearch(input, "la")
+		out := input.out.(*bytes.Buffer)
+		out.Reset()
+
+		input.render()
+
+		assert.Contains(t, out.String(), "(reverse-i-search)`la': ls -\033[1;38;2;250;204;21mla\033[0m")
+	})
+}
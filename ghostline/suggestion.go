@@ -38,112 +38,122 @@ type scoredMatch struct {
 	isPrefix bool   // true if suggestion starts with the typed word
 }
 
-// getMatches returns all suggestions matching the last word in the buffer.
-// Combines prefix matching and fuzzy matching, sorted by relevance.
-// Prefix matches appear first, followed by fuzzy matches sorted by score.
-// Returns nil if the buffer is empty or no matches are found.
-// Results are cached to avoid recomputation on every render.
-func (i *Input) getMatches() []string {
+// getCandidates returns the completion candidates for the current input.
+// Returns nil if the buffer is empty or the completer offers nothing.
+// Results are cached to avoid recomputation on every render; with an
+// AsyncCompleter, computing them also starts a background completion.
+func (i *Input) getCandidates() []Candidate {
 	if len(i.buffer) == 0 {
 		return nil
 	}
 
 	bufferStr := string(i.buffer)
 
-	// Return cached result if buffer hasn't changed
-	if i.cachedMatchesFor == bufferStr {
-		return i.cachedMatches
-	}
-
-	lastWord := extractLastWord(bufferStr)
-	if lastWord == "" {
-		i.cachedMatchesFor = bufferStr
-		i.cachedMatches = nil
-		return nil
+	// Return cached result if buffer and cursor haven't changed
+	if i.cachedFor == bufferStr && i.cachedCursor == i.cursorPos {
+		return i.cachedCandidates
 	}
 
-	var matches []scoredMatch
-
-	for _, s := range i.suggestions {
-		isPrefix := strings.HasPrefix(strings.ToLower(s), strings.ToLower(lastWord))
-		score := fuzzyScore(lastWord, s)
+	completer := i.activeCompleter()
+	i.cachedFor = bufferStr
+	i.cachedCursor = i.cursorPos
+	i.cachedCandidates = validCandidates(completer.Complete(i.buffer, i.cursorPos), len(i.buffer))
 
-		if isPrefix || score >= 0 {
-			matches = append(matches, scoredMatch{
-				text:     s,
-				score:    score,
-				isPrefix: isPrefix,
-			})
-		}
+	if async, ok := completer.(AsyncCompleter); ok {
+		i.startCompletion(async)
 	}
+	return i.cachedCandidates
+}
 
-	if len(matches) == 0 {
-		i.cachedMatchesFor = bufferStr
-		i.cachedMatches = nil
+// getMatches returns the text of every completion candidate, in order.
+// With the default completer these are the suggestions matching the last word
+// in the buffer: prefix matches first, followed by fuzzy matches sorted by score.
+// Returns nil if the buffer is empty or no matches are found.
+func (i *Input) getMatches() []string {
+	candidates := i.getCandidates()
+	if len(candidates) == 0 {
 		return nil
 	}
 
-	slices.SortFunc(matches, func(a, b scoredMatch) int {
-		if a.isPrefix != b.isPrefix {
-			if a.isPrefix {
-				return -1
-			}
-			return 1
-		}
-		if b.score != a.score {
-			return b.score - a.score
-		}
-		return strings.Compare(strings.ToLower(a.text), strings.ToLower(b.text))
-	})
-
-	result := make([]string, len(matches))
-	for idx, m := range matches {
-		result[idx] = m.text
+	matches := make([]string, len(candidates))
+	for idx, c := range candidates {
+		matches[idx] = c.Text
 	}
+	return matches
+}
 
-	i.cachedMatchesFor = bufferStr
-	i.cachedMatches = result
-	return result
+// selectedCandidate returns the candidate chosen by matchIndex, wrapping at the end.
+// Reports false if there are no candidates.
+func (i *Input) selectedCandidate() (Candidate, bool) {
+	candidates := i.getCandidates()
+	if len(candidates) == 0 {
+		return Candidate{}, false
+	}
+	return candidates[i.matchIndex%len(candidates)], true
+}
+
+// completing reports whether the candidates apply at the cursor: always at the
+// end of the buffer, and in the middle when the selected candidate's range ends
+// at the cursor, as for a flag or path a Completer offers mid-line.
+func (i *Input) completing() bool {
+	if i.cursorPos == len(i.buffer) {
+		return true
+	}
+	c, ok := i.selectedCandidate()
+	return ok && c.End == i.cursorPos
 }
 
 // findMatch returns the currently selected suggestion for Tab completion.
 // Cycles through matches based on matchIndex, wrapping at the end.
 // Returns empty string if no matches exist.
 func (i *Input) findMatch() string {
-	matches := i.getMatches()
-	if len(matches) == 0 {
-		return ""
-	}
-	idx := i.matchIndex % len(matches)
-	return matches[idx]
+	c, _ := i.selectedCandidate()
+	return c.Text
 }
 
 // lastWordStart returns the buffer index where the last word begins.
-// Used to determine how much text to replace when accepting a suggestion.
 func (i *Input) lastWordStart() int {
-	text := string(i.buffer)
-	lastWord := extractLastWord(text)
-	return len(i.buffer) - len([]rune(lastWord))
+	return lastWordStart(i.buffer)
+}
+
+// lastWordStart returns the index in buffer where its last word begins.
+func lastWordStart(buffer []rune) int {
+	return len(buffer) - len([]rune(extractLastWord(string(buffer))))
+}
+
+// acceptCandidate replaces the candidate's range with its text, moving the
+// cursor after the inserted text.
+func (i *Input) acceptCandidate(c Candidate) {
+	i.replaceRange(c.Start, c.End, c.Text)
+	i.matchIndex = 0
+	i.render()
+}
+
+// replaceRange replaces the buffer runes in [start, end) with text and moves
+// the cursor after the inserted text.
+func (i *Input) replaceRange(start, end int, text string) {
+	inserted := []rune(text)
+	i.buffer = slices.Concat(i.buffer[:start], inserted, i.buffer[end:])
+	i.cursorPos = start + len(inserted)
 }
 
 // findGhost returns the ghost text portion to display after the cursor.
-// Ghost text is the untyped suffix of the current suggestion.
-// Returns empty string if no suggestion matches or cursor is mid-buffer.
+// Ghost text is the untyped suffix of the current candidate: its text minus
+// as many runes as it replaces. Only candidates replacing up to the end of the
+// buffer produce ghost text, since it is drawn after the last character.
+// Returns empty string if no candidate applies.
 //
 // Example: If user typed "hel" and suggestion is "hello", returns "lo".
 func (i *Input) findGhost() string {
-	text := string(i.buffer)
-	lastWord := extractLastWord(text)
-	if lastWord == "" {
+	c, ok := i.selectedCandidate()
+	if !ok || c.End != len(i.buffer) {
 		return ""
 	}
 
-	match := i.findMatch()
-	lastWordRunes := []rune(lastWord)
-	matchRunes := []rune(match)
-
-	if match == "" || len(matchRunes) <= len(lastWordRunes) {
+	typed := c.End - c.Start
+	text := []rune(c.Text)
+	if typed == 0 || len(text) <= typed {
 		return ""
 	}
-	return string(matchRunes[len(lastWordRunes):])
+	return string(text[typed:])
 }
@@ -1,8 +1,20 @@
 package ghostline
 
-import "golang.org/x/term"
+import (
+	"fmt"
 
-// enableRawMode puts the terminal into raw mode for character-by-character input.
+	"golang.org/x/term"
+)
+
+// Bracketed paste mode makes the terminal wrap pasted text in
+// ESC [ 200 ~ ... ESC [ 201 ~, so a paste can be told apart from typing.
+const (
+	enableBracketedPaste  = "\033[?2004h"
+	disableBracketedPaste = "\033[?2004l"
+)
+
+// enableRawMode puts the terminal into raw mode for character-by-character input
+// and turns on bracketed paste mode.
 // Saves the original state for later restoration by disableRawMode.
 func (i *Input) enableRawMode() error {
 	state, err := term.MakeRaw(i.fd)
@@ -10,13 +22,16 @@ func (i *Input) enableRawMode() error {
 		return err
 	}
 	i.oldState = state
+	_, _ = fmt.Fprint(i.out, enableBracketedPaste)
 	return nil
 }
 
-// disableRawMode restores the terminal to its original state.
+// disableRawMode turns off bracketed paste mode and restores the terminal to
+// its original state.
 // Safe to call even if enableRawMode was never called.
 func (i *Input) disableRawMode() {
 	if i.oldState != nil {
+		_, _ = fmt.Fprint(i.out, disableBracketedPaste)
 		_ = term.Restore(i.fd, i.oldState)
 	}
 }
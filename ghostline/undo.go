@@ -0,0 +1,119 @@
+package ghostline
+
+import (
+	"bufio"
+	"slices"
+)
+
+// editState is a snapshot of the buffer and cursor, restored by undo.
+type editState struct {
+	buffer []rune
+	cursor int
+}
+
+// snapshot returns the current buffer and cursor.
+func (i *Input) snapshot() editState {
+	return editState{buffer: slices.Clone(i.buffer), cursor: i.cursorPos}
+}
+
+// resetUndo forgets all undo and redo steps; each Readline call starts afresh.
+func (i *Input) resetUndo() {
+	i.undo = nil
+	i.redo = nil
+	i.undoGroup = false
+	i.undoing = false
+}
+
+// trackUndo records before, the state preceding a keystroke, as an undo step
+// if the keystroke changed the buffer. Every mutation goes through the key
+// dispatch in Readline, so typing, deletions, kills and yanks, completions and
+// history recall are all undoable. Cursor movements alone are not recorded.
+//
+// Consecutive typed characters merge into one step, and in vi insert mode all
+// changes up to the next Escape do, as in vi.
+func (i *Input) trackUndo(before editState) {
+	if i.undoing {
+		// The keystroke was an undo or redo; its own change is not a new step
+		i.undoing = false
+		return
+	}
+	if slices.Equal(before.buffer, i.buffer) {
+		// Moving the cursor ends a run of typed characters
+		i.undoGroup = i.undoGroup && i.groupsUndo()
+		return
+	}
+
+	if !i.undoGroup || !i.groupsUndo() {
+		i.pushUndo(before)
+	}
+	i.undoGroup = i.groupsUndo()
+}
+
+// groupsUndo reports whether the current keystroke's change merges with the
+// changes around it into one undo step.
+func (i *Input) groupsUndo() bool {
+	if i.vi != nil && !i.vi.normal {
+		return true
+	}
+	return i.thisCommand == commandInsert
+}
+
+// pushUndo adds state as the latest undo step. A new change discards the
+// steps available to redo.
+func (i *Input) pushUndo(state editState) {
+	i.undo = append(i.undo, state)
+	i.redo = nil
+}
+
+// undoEdit restores the state before the latest undo step.
+// Reports false if there is nothing to undo.
+func (i *Input) undoEdit() bool {
+	if len(i.undo) == 0 {
+		return false
+	}
+
+	state := i.undo[len(i.undo)-1]
+	i.undo = i.undo[:len(i.undo)-1]
+	i.redo = append(i.redo, i.snapshot())
+	i.restore(state)
+	return true
+}
+
+// redoEdit reapplies the latest change reverted by undoEdit.
+// Reports false if there is nothing to redo.
+func (i *Input) redoEdit() bool {
+	if len(i.redo) == 0 {
+		return false
+	}
+
+	state := i.redo[len(i.redo)-1]
+	i.redo = i.redo[:len(i.redo)-1]
+	i.undo = append(i.undo, i.snapshot())
+	i.restore(state)
+	return true
+}
+
+// restore replaces the buffer and cursor with state for undo and redo.
+func (i *Input) restore(state editState) {
+	i.buffer = state.buffer
+	i.cursorPos = state.cursor
+	i.undoGroup = false
+	i.undoing = true
+	i.matchIndex = 0
+}
+
+// handleUndo reverts the latest change (Ctrl+_ or Ctrl+Z).
+func handleUndo(i *Input, _ *bufio.Reader) (string, action) {
+	if i.undoEdit() {
+		i.render()
+	}
+	return "", actionContinue
+}
+
+// handleRedo reapplies the latest undone change (Alt+/).
+func handleRedo(i *Input, _ *bufio.Reader) (string, action) {
+	if i.redoEdit() {
+		i.render()
+	}
+	return "", actionContinue
+}
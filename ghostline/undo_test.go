@@ -0,0 +1,133 @@
+package ghostline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUndo(t *testing.T) {
+	t.Parallel()
+
+	t.Run("typed characters are one step", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("", 0)
+		input.handlers = defaultHandlers()
+
+		pressKeys(input, "git")
+		pressKey(input, keyCtrlUnder, "")
+
+		assert.Empty(t, string(input.buffer))
+		assert.Equal(t, 0, input.cursorPos)
+	})
+
+	t.Run("cursor movement ends a typing step", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("", 0)
+		input.handlers = defaultHandlers()
+
+		pressKeys(input, "ab")
+		pressKey(input, keyCtrlA, "")
+		pressKeys(input, "x")
+		pressKey(input, keyCtrlZ, "")
+
+		assert.Equal(t, "ab", string(input.buffer))
+		assert.Equal(t, 0, input.cursorPos)
+	})
+
+	t.Run("kills and yanks are undoable", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("hello world", 5)
+		input.handlers = defaultHandlers()
+
+		pressKey(input, keyCtrlK, "")
+		pressKey(input, keyCtrlY, "")
+		pressKey(input, keyCtrlY, "")
+
+		pressKey(input, keyCtrlUnder, "")
+		assert.Equal(t, "hello world", string(input.buffer))
+
+		pressKey(input, keyCtrlUnder, "")
+		pressKey(input, keyCtrlUnder, "")
+		assert.Equal(t, "hello world", string(input.buffer))
+		assert.Equal(t, 5, input.cursorPos)
+	})
+
+	t.Run("Tab completion is undoable", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("", 0)
+		input.handlers = defaultHandlers()
+		input.suggestions = []string{"checkout"}
+
+		pressKeys(input, "che")
+		pressKey(input, keyTab, "")
+		assert.Equal(t, "checkout", string(input.buffer))
+
+		pressKey(input, keyCtrlUnder, "")
+		assert.Equal(t, "che", string(input.buffer))
+	})
+
+	t.Run("history recall is undoable", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("draft", 5)
+		input.handlers = defaultHandlers()
+		input.history.Add("ls -la")
+
+		pressKey(input, keyEscape, "[A")
+		assert.Equal(t, "ls -la", string(input.buffer))
+
+		pressKey(input, keyCtrlUnder, "")
+		assert.Equal(t, "draft", string(input.buffer))
+	})
+
+	t.Run("Alt+/ redoes undone changes", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("one two", 7)
+		input.handlers = defaultHandlers()
+
+		pressKey(input, keyCtrlW, "")
+		pressKey(input, keyCtrlW, "")
+		pressKey(input, keyCtrlUnder, "")
+		pressKey(input, keyCtrlUnder, "")
+		assert.Equal(t, "one two", string(input.buffer))
+
+		pressKey(input, keyEscape, "/")
+		assert.Equal(t, "one ", string(input.buffer))
+
+		pressKey(input, keyEscape, "/")
+		pressKey(input, keyEscape, "/")
+		assert.Empty(t, string(input.buffer))
+	})
+
+	t.Run("new change discards redo", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("ab", 2)
+		input.handlers = defaultHandlers()
+
+		pressKey(input, keyBackspace, "")
+		pressKey(input, keyCtrlUnder, "")
+		pressKeys(input, "c")
+		pressKey(input, keyEscape, "/")
+
+		assert.Equal(t, "abc", string(input.buffer))
+	})
+
+	t.Run("undo with nothing to undo", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("abc", 1)
+		input.handlers = defaultHandlers()
+
+		pressKey(input, keyCtrlUnder, "")
+
+		assert.Equal(t, "abc", string(input.buffer))
+		assert.Equal(t, 1, input.cursorPos)
+	})
+}
@@ -0,0 +1,119 @@
+package ghostline
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// ErrIncomplete is returned by a Validator when the input is not finished yet,
+// such as an unclosed bracket or an SQL statement without its semicolon.
+// Enter then starts a new line instead of submitting.
+var ErrIncomplete = errors.New("incomplete input")
+
+// Validator decides whether the input can be submitted when Enter is pressed.
+// Validate returns nil to submit, ErrIncomplete (or an error wrapping it) to
+// continue the input on a new line, or any other error to keep the input
+// open and show the error's message below it until the next keystroke.
+// It must not modify or retain buffer.
+type Validator interface {
+	Validate(buffer []rune) error
+}
+
+// ValidatorFunc adapts an ordinary function to the Validator interface.
+//
+// Example:
+//
+//	// SQL statements end with a semicolon
+//	input.SetValidator(ghostline.ValidatorFunc(func(buffer []rune) error {
+//		if !strings.HasSuffix(strings.TrimSpace(string(buffer)), ";") {
+//			return ghostline.ErrIncomplete
+//		}
+//		return nil
+//	}))
+type ValidatorFunc func(buffer []rune) error
+
+// Validate calls f(buffer).
+func (f ValidatorFunc) Validate(buffer []rune) error {
+	return f(buffer)
+}
+
+// SetValidator sets the check run when Enter is pressed.
+// A nil validator makes Enter always submit.
+func (i *Input) SetValidator(v Validator) {
+	i.validator = v
+}
+
+// BracketValidator returns a Validator for expression-like input: the input
+// is incomplete while a (, [ or { is unclosed, a quoted string is
+// unterminated or the last line ends with a backslash, and invalid when a
+// closing bracket does not match the open one.
+//
+// Example:
+//
+//	input.SetValidator(ghostline.BracketValidator())
+//	// "print(1," + Enter continues on a new line; "f(]" shows "unexpected ']'"
+func BracketValidator() Validator {
+	return ValidatorFunc(validateBrackets)
+}
+
+// closers maps each opening bracket to its closing bracket.
+var closers = map[rune]rune{'(': ')', '[': ']', '{': '}'}
+
+// validateBrackets implements BracketValidator.
+func validateBrackets(buffer []rune) error {
+	var open []rune // closing brackets expected, innermost last
+	var quote rune  // quote character of the string being scanned, 0 outside strings
+	continued := false
+
+	for idx := 0; idx < len(buffer); idx++ {
+		r := buffer[idx]
+		switch {
+		case r == '\\':
+			continued = idx == len(buffer)-1
+			idx++ // skip the escaped character
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'' || r == '`':
+			quote = r
+		case closers[r] != 0:
+			open = append(open, closers[r])
+		case r == ')' || r == ']' || r == '}':
+			if len(open) == 0 || open[len(open)-1] != r {
+				return fmt.Errorf("unexpected '%c'", r)
+			}
+			open = open[:len(open)-1]
+		}
+	}
+
+	if len(open) > 0 || quote != 0 || continued {
+		return ErrIncomplete
+	}
+	return nil
+}
+
+// renderValidation draws the validation error on the row below the input,
+// aligned with the text after the prompt. Returns the number of rows written.
+func (i *Input) renderValidation() int {
+	if i.validationErr == nil {
+		return 0
+	}
+
+	prompt := i.firstPrompt()
+	if i.findLineStartFrom(len(i.buffer)) > 0 {
+		prompt = i.contPrompt
+	}
+	indent := visibleWidth(prompt)
+
+	msg := i.validationErr.Error()
+	if width := i.termWidth() - 1; width > indent {
+		msg = runewidth.Truncate(msg, width-indent, "…")
+	}
+
+	// Validation error (color: #ef4444)
+	_, _ = fmt.Fprintf(i.out, "\n\r%*s\033[38;2;239;68;68m%s\033[0m", indent, "", msg)
+	return 1
+}
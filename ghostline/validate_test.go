@@ -0,0 +1,112 @@
+package ghostline
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBracketValidator(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		want  error
+	}{
+		{"empty", "", nil},
+		{"balanced", "f(a[1], {b})", nil},
+		{"unclosed paren", "print(1,", ErrIncomplete},
+		{"unclosed nested", "{a: [1, 2", ErrIncomplete},
+		{"unterminated string", `say("hi`, ErrIncomplete},
+		{"brackets inside strings", `say(")")`, nil},
+		{"escaped quote", `say("a\")`, ErrIncomplete},
+		{"trailing backslash", "ls \\", ErrIncomplete},
+		{"escaped backslash", "echo \\\\", nil},
+		{"line continuation", "ls \\\n-la", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, BracketValidator().Validate([]rune(tt.input)))
+		})
+	}
+
+	t.Run("mismatched bracket", func(t *testing.T) {
+		t.Parallel()
+		assert.EqualError(t, BracketValidator().Validate([]rune("f(]")), "unexpected ']'")
+	})
+}
+
+func TestValidator(t *testing.T) {
+	t.Parallel()
+
+	sql := ValidatorFunc(func(buffer []rune) error {
+		text := strings.TrimSpace(string(buffer))
+		if strings.HasPrefix(text, "drop") {
+			return errors.New("drop is not allowed")
+		}
+		if !strings.HasSuffix(text, ";") {
+			return fmt.Errorf("statement: %w", ErrIncomplete)
+		}
+		return nil
+	})
+
+	t.Run("Enter submits complete input", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("select 1;", 9)
+		input.SetValidator(sql)
+
+		result, act := handleEnter(input, nil)
+
+		assert.Equal(t, actionSubmit, act)
+		assert.Equal(t, "select 1;", result)
+	})
+
+	t.Run("Enter continues incomplete input on a new line", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("select 1", 8)
+		input.prompt, input.contPrompt = "> ", "... "
+		input.SetValidator(sql)
+		out := input.out.(*bytes.Buffer)
+
+		_, act := handleEnter(input, nil)
+
+		assert.Equal(t, actionContinue, act)
+		assert.Equal(t, "select 1\n", string(input.buffer))
+		assert.Equal(t, 9, input.cursorPos)
+		assert.Contains(t, out.String(), "> select 1\n\r... ")
+	})
+
+	t.Run("Enter shows validation errors below the input", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("drop table t;", 13)
+		input.prompt = "> "
+		input.SetValidator(sql)
+		out := input.out.(*bytes.Buffer)
+
+		_, act := handleEnter(input, nil)
+
+		assert.Equal(t, actionContinue, act)
+		assert.Equal(t, "drop table t;", string(input.buffer))
+		assert.Equal(t, "\r\033[J> drop table t;\n\r  \033[38;2;239;68;68mdrop is not allowed\033[0m\033[1A\r\033[16G", out.String())
+	})
+
+	t.Run("Enter submits without a validator", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("select 1", 8)
+
+		_, act := handleEnter(input, nil)
+
+		assert.Equal(t, actionSubmit, act)
+	})
+}
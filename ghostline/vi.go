@@ -0,0 +1,613 @@
+package ghostline
+
+import (
+	"bufio"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// EditMode selects the key bindings used by Readline.
+type EditMode int
+
+const (
+	// EmacsMode uses emacs-style bindings such as Ctrl+A and Ctrl+K (the default).
+	EmacsMode EditMode = iota
+	// ViMode adds vi insert and normal modes. Each line starts in insert mode,
+	// which keeps the emacs-style bindings; Escape switches to normal mode.
+	ViMode
+)
+
+// Mode indicators shown before the prompt in vi mode.
+const (
+	viInsertIndicator = "(ins) "
+	viNormalIndicator = "(cmd) "
+)
+
+// Normal mode keys, grouped by how they combine.
+const (
+	viOperators   = "dcy"              // take a motion: dw, c$, y2e; doubled for whole lines: dd
+	viMotions     = "hlwbe0^$fFtT"     // move the cursor, alone or after an operator
+	viCharMotions = "fFtT"             // motions followed by a character argument
+	viCommands    = "xXDCsSpPiaIAujk." // standalone commands
+)
+
+// viAliases are normal mode commands that are shorthands for an operator and
+// a motion.
+var viAliases = map[rune]viCommand{
+	'x': {op: 'd', key: 'l'},
+	'X': {op: 'd', key: 'h'},
+	'D': {op: 'd', key: '$'},
+	'C': {op: 'c', key: '$'},
+	's': {op: 'c', key: 'l'},
+	'S': {op: 'c', key: 'c'},
+}
+
+// maxViCount caps command counts so a mistyped count cannot stall input.
+const maxViCount = 9999
+
+// viCommand is a parsed normal mode command: [count] [operator] key [char].
+type viCommand struct {
+	count int  // repeat count; 0 when none was typed
+	op    rune // operator 'd', 'c' or 'y'; 0 for motions and other commands
+	key   rune // motion or command key; equal to op for whole-line operations
+	arg   rune // character argument of f, F, t and T
+}
+
+// times returns the repeat count, defaulting to 1.
+func (c viCommand) times() int {
+	return max(c.count, 1)
+}
+
+// isChange reports whether the command modifies the buffer, making it the
+// command repeated by '.'.
+func (c viCommand) isChange() bool {
+	if c.op != 0 {
+		return c.op != 'y'
+	}
+	return strings.ContainsRune("xXDCsSpPiaIA", c.key)
+}
+
+// viState holds the vi mode state of an Input.
+type viState struct {
+	normal   bool   // normal mode; insert mode otherwise
+	pending  []rune // keys of the normal mode command typed so far
+	register []rune // text of the last yank or delete, put back by p and P
+	linewise bool   // register holds whole lines, put on lines of their own
+
+	last       viCommand // last change, repeated by '.'
+	lastText   []rune    // text typed in insert mode as part of the last change
+	recording  bool      // the current insert session belongs to last
+	insertFrom int       // cursor position where the insert session started
+}
+
+// reset prepares vi mode for a new line, which starts in insert mode.
+// The register and the change repeated by '.' are kept across lines.
+func (v *viState) reset() {
+	v.normal = false
+	v.pending = nil
+	v.recording = false
+}
+
+// SetEditMode selects emacs or vi key bindings.
+//
+// In vi mode the prompt is preceded by a mode indicator, "(ins) " or "(cmd) ".
+// Normal mode supports:
+//   - motions: h l w b e 0 ^ $ f t F T, with counts (3w)
+//   - operators: d c y with a motion or doubled for the line (dw, c2e, yy, 3dd)
+//   - x X D C s S: shorthands for dl dh d$ c$ cl cc
+//   - p P: put the last yanked or deleted text after or before the cursor
+//   - i a I A: enter insert mode
+//   - u: undo, Ctrl+R: redo, . : repeat the last change
+//   - k j: previous/next history entry
+//
+// Enter, Ctrl+C, Ctrl+D and the arrow keys work in both modes.
+// Escape waits 50ms for the rest of an escape sequence, so arrow keys split
+// across reads by ssh or a slow pty are not taken as vi commands.
+//
+// Example:
+//
+//	input := ghostline.NewInput(suggestions, nil, nil)
+//	input.SetEditMode(ghostline.ViMode)
+func (i *Input) SetEditMode(mode EditMode) {
+	if mode != ViMode {
+		i.vi = nil
+		return
+	}
+	if i.vi == nil {
+		i.vi = &viState{}
+	}
+}
+
+// modeIndicator returns the vi mode indicator shown before the prompt,
+// or "" in emacs mode.
+func (i *Input) modeIndicator() string {
+	switch {
+	case i.vi == nil:
+		return ""
+	case i.vi.normal:
+		return viNormalIndicator
+	default:
+		return viInsertIndicator
+	}
+}
+
+// enterNormalMode switches from insert to normal mode, as Escape does in vi:
+// the insert session ends and the cursor moves back onto the last inserted
+// character.
+func (i *Input) enterNormalMode() {
+	v := i.vi
+	if v.recording {
+		if v.insertFrom <= i.cursorPos && i.cursorPos <= len(i.buffer) {
+			v.lastText = slices.Clone(i.buffer[v.insertFrom:i.cursorPos])
+		}
+		v.recording = false
+	}
+
+	v.normal = true
+	v.pending = nil
+	i.undoGroup = false
+	if i.cursorPos > i.findLineStart() {
+		i.cursorPos--
+	}
+	i.render()
+}
+
+// enterInsertMode switches to insert mode at the cursor. With record set the
+// text typed until Escape becomes part of the change repeated by '.'.
+func (i *Input) enterInsertMode(record bool) {
+	v := i.vi
+	v.normal = false
+	v.recording = record
+	v.insertFrom = i.cursorPos
+}
+
+// handleViNormal processes a keystroke in vi normal mode. Keys accumulate
+// until they form a complete command, which is then executed; keys that
+// cannot form a command are dropped. Control keys such as Enter and Ctrl+C
+// are dispatched to the regular handlers.
+func (i *Input) handleViNormal(r rune, reader *bufio.Reader) (string, action) {
+	v := i.vi
+
+	if r == keyEscape {
+		v.pending = nil
+		// Escape sequences such as arrow keys follow at once; a lone Escape
+		// only cancels the pending command.
+		if i.sequenceFollows(reader) {
+			return handleEscape(i, reader)
+		}
+		return "", actionContinue
+	}
+
+	if len(v.pending) == 0 && r == keyCtrlR {
+		// Ctrl+R redoes in normal mode, as in vi, rather than searching history
+		if i.redoEdit() {
+			i.clampViCursor()
+			i.render()
+		}
+		return "", actionContinue
+	}
+
+	if len(v.pending) == 0 && !unicode.IsPrint(r) {
+		if handler, ok := i.handlers[r]; ok {
+			return handler(i, reader)
+		}
+		return "", actionContinue
+	}
+
+	v.pending = append(v.pending, r)
+	cmd, done, ok := parseViCommand(v.pending)
+	if !ok {
+		v.pending = nil
+		return "", actionContinue
+	}
+	if !done {
+		return "", actionContinue
+	}
+
+	v.pending = nil
+	i.execVi(cmd)
+	if v.normal {
+		i.clampViCursor()
+	}
+	i.matchIndex = 0
+	i.render()
+	return "", actionContinue
+}
+
+// parseViCommand parses normal mode keys of the form
+// [count] [operator [count]] key [char]. Reports done when keys form a
+// complete command, and ok = false when they cannot start a valid one.
+func parseViCommand(keys []rune) (cmd viCommand, done, ok bool) {
+	idx := 0
+	cmd.count = parseViCount(keys, &idx)
+	if idx == len(keys) {
+		return cmd, false, true
+	}
+
+	if strings.ContainsRune(viOperators, keys[idx]) {
+		cmd.op = keys[idx]
+		idx++
+		if n := parseViCount(keys, &idx); n > 0 {
+			cmd.count = min(cmd.times()*n, maxViCount)
+		}
+		if idx == len(keys) {
+			return cmd, false, true
+		}
+	}
+
+	cmd.key = keys[idx]
+	idx++
+	switch {
+	case cmd.op != 0 && cmd.key == cmd.op:
+	case strings.ContainsRune(viMotions, cmd.key):
+	case cmd.op == 0 && strings.ContainsRune(viCommands, cmd.key):
+	default:
+		return cmd, false, false
+	}
+
+	if strings.ContainsRune(viCharMotions, cmd.key) {
+		if idx == len(keys) {
+			return cmd, false, true
+		}
+		cmd.arg = keys[idx]
+		idx++
+	}
+	return cmd, idx == len(keys), idx == len(keys)
+}
+
+// parseViCount reads a count at keys[*idx], advancing past it.
+// Returns 0 when there is none; a leading 0 is the motion, not a count.
+func parseViCount(keys []rune, idx *int) int {
+	n := 0
+	for *idx < len(keys) && keys[*idx] >= '0' && keys[*idx] <= '9' {
+		if keys[*idx] == '0' && n == 0 {
+			break
+		}
+		n = min(n*10+int(keys[*idx]-'0'), maxViCount)
+		*idx++
+	}
+	return n
+}
+
+// execVi executes a complete normal mode command.
+func (i *Input) execVi(cmd viCommand) {
+	v := i.vi
+
+	switch cmd.key {
+	case 'u':
+		for range cmd.times() {
+			if !i.undoEdit() {
+				break
+			}
+		}
+		return
+	case '.':
+		i.repeatViChange(cmd.count)
+		return
+	case 'k':
+		if entry, ok := i.history.Previous(string(i.buffer)); ok {
+			i.buffer, i.cursorPos = []rune(entry), 0
+		}
+		return
+	case 'j':
+		if entry, ok := i.history.Next(); ok {
+			i.buffer, i.cursorPos = []rune(entry), 0
+		}
+		return
+	}
+
+	if cmd.isChange() {
+		v.last, v.lastText = cmd, nil
+	}
+	if alias, ok := viAliases[cmd.key]; ok && cmd.op == 0 {
+		alias.count = cmd.count
+		cmd = alias
+	}
+
+	switch {
+	case cmd.op != 0:
+		i.viOperate(cmd)
+	case strings.ContainsRune(viMotions, cmd.key):
+		if target, _, ok := i.viMotion(cmd.key, cmd.arg, cmd.times()); ok {
+			i.cursorPos = target
+		}
+	case cmd.key == 'p' || cmd.key == 'P':
+		i.viPut(cmd.key == 'p', cmd.times())
+	default:
+		i.viInsert(cmd.key)
+	}
+}
+
+// viOperate applies an operator to the text between the cursor and the
+// target of its motion, or to whole lines when the operator is doubled.
+// The text is stored in the register.
+func (i *Input) viOperate(cmd viCommand) {
+	v := i.vi
+	var start, end int
+	if cmd.key == cmd.op {
+		start, end = i.viLines(cmd.times())
+		v.register, v.linewise = slices.Clone(i.buffer[start:end]), true
+		if cmd.op == 'd' {
+			// Remove the line break separating the lines from the rest
+			if end < len(i.buffer) {
+				end++
+			} else if start > 0 {
+				start--
+			}
+		}
+	} else {
+		key := cmd.key
+		// cw changes to the end of the word, like ce
+		if cmd.op == 'c' && key == 'w' && i.cursorPos < len(i.buffer) && viClass(i.buffer[i.cursorPos]) != 0 {
+			key = 'e'
+		}
+
+		target, inclusive, ok := i.viMotion(key, cmd.arg, cmd.times())
+		if !ok {
+			return
+		}
+		start, end = min(i.cursorPos, target), max(i.cursorPos, target)
+		if inclusive {
+			end = min(end+1, len(i.buffer))
+		}
+		v.register, v.linewise = slices.Clone(i.buffer[start:end]), false
+	}
+
+	switch cmd.op {
+	case 'y':
+		i.cursorPos = start
+	case 'd':
+		i.buffer = slices.Delete(i.buffer, start, end)
+		i.cursorPos = start
+		if v.linewise {
+			i.cursorPos = i.findLineStart()
+		}
+	case 'c':
+		i.buffer = slices.Delete(i.buffer, start, end)
+		i.cursorPos = start
+		i.enterInsertMode(true)
+	}
+}
+
+// viLines returns the range of count lines starting at the cursor's line,
+// without the line break after the last one.
+func (i *Input) viLines(count int) (start, end int) {
+	start, end = i.findLineStart(), i.findLineEnd()
+	for range count - 1 {
+		if end == len(i.buffer) {
+			break
+		}
+		end = i.findLineEndFrom(end + 1)
+	}
+	return start, end
+}
+
+// viPut inserts the register count times after the cursor, or before it.
+// The cursor ends on the last inserted character. Whole lines are put as
+// lines of their own below or above the cursor's line, with the cursor at the
+// start of the first one.
+func (i *Input) viPut(after bool, count int) {
+	v := i.vi
+	if len(v.register) == 0 && !v.linewise {
+		return
+	}
+
+	if v.linewise {
+		lines := slices.Repeat(append(slices.Clone(v.register), '\n'), count)
+		at := i.findLineStart()
+		if after {
+			// Move the line break to the front: "\nline" after the line end
+			lines = append([]rune{'\n'}, lines[:len(lines)-1]...)
+			at = i.findLineEnd()
+		}
+		i.buffer = slices.Insert(i.buffer, at, lines...)
+		i.cursorPos = at
+		if after {
+			i.cursorPos++
+		}
+		return
+	}
+
+	at := i.cursorPos
+	if after && at < len(i.buffer) {
+		at++
+	}
+	text := slices.Repeat(v.register, count)
+	i.buffer = slices.Insert(i.buffer, at, text...)
+	i.cursorPos = at + len(text) - 1
+}
+
+// viInsert enters insert mode for the i, a, I and A commands.
+func (i *Input) viInsert(key rune) {
+	switch key {
+	case 'a':
+		if i.cursorPos < i.findLineEnd() {
+			i.cursorPos++
+		}
+	case 'I':
+		i.cursorPos = i.findLineStart()
+		for i.cursorPos < len(i.buffer) && (i.buffer[i.cursorPos] == ' ' || i.buffer[i.cursorPos] == '\t') {
+			i.cursorPos++
+		}
+	case 'A':
+		i.cursorPos = i.findLineEnd()
+	}
+	i.enterInsertMode(true)
+}
+
+// repeatViChange repeats the last change for '.', with count replacing its
+// original count when given. Text typed in insert mode as part of the change
+// is inserted again.
+func (i *Input) repeatViChange(count int) {
+	v := i.vi
+	last, text := v.last, v.lastText
+	if last.key == 0 {
+		return
+	}
+	if count > 0 {
+		last.count = count
+	}
+
+	i.execVi(last)
+	if !v.normal {
+		// The change entered insert mode: type the text and leave it again
+		i.buffer = slices.Insert(i.buffer, i.cursorPos, text...)
+		i.cursorPos += len(text)
+		v.normal, v.recording = true, false
+		if i.cursorPos > i.findLineStart() {
+			i.cursorPos--
+		}
+	}
+	v.last, v.lastText = last, text
+}
+
+// viMotion returns the cursor position a motion moves to, repeated count
+// times. Inclusive motions (e, $, f, t) cover the target character when used
+// with an operator. Reports false if the motion fails, e.g. f finds no match.
+func (i *Input) viMotion(key, arg rune, count int) (target int, inclusive, ok bool) {
+	pos := i.cursorPos
+	lineStart, lineEnd := i.findLineStart(), i.findLineEnd()
+
+	switch key {
+	case 'h':
+		return max(pos-count, lineStart), false, pos > lineStart
+	case 'l':
+		return min(pos+count, lineEnd), false, pos < lineEnd
+	case '0':
+		return lineStart, false, true
+	case '^':
+		for pos = lineStart; pos < lineEnd && viClass(i.buffer[pos]) == 0; pos++ {
+		}
+		return pos, false, true
+	case '$':
+		if lineEnd == lineStart {
+			return lineStart, false, true
+		}
+		return lineEnd - 1, true, true
+	case 'w':
+		for range count {
+			pos = i.viNextWordStart(pos)
+		}
+		return pos, false, true
+	case 'b':
+		for range count {
+			pos = i.viPrevWordStart(pos)
+		}
+		return pos, false, true
+	case 'e':
+		for range count {
+			pos = i.viWordEnd(pos)
+		}
+		return pos, true, pos < len(i.buffer)
+	case 'f', 't':
+		for range count {
+			if pos+1 > lineEnd {
+				return 0, false, false
+			}
+			next := slices.Index(i.buffer[pos+1:lineEnd], arg)
+			if next < 0 {
+				return 0, false, false
+			}
+			pos += 1 + next
+		}
+		if key == 't' {
+			pos--
+		}
+		return pos, true, true
+	case 'F', 'T':
+		for range count {
+			prev := pos - 1
+			for prev >= lineStart && i.buffer[prev] != arg {
+				prev--
+			}
+			if prev < lineStart {
+				return 0, false, false
+			}
+			pos = prev
+		}
+		if key == 'T' {
+			pos++
+		}
+		return pos, false, true
+	}
+	return 0, false, false
+}
+
+// viClass returns the vi character class of r: 0 for blanks, 1 for word
+// characters (letters, digits, underscore), 2 for other punctuation.
+// A vi word is a run of characters of class 1 or of class 2.
+func viClass(r rune) int {
+	switch {
+	case r == ' ' || r == '\t' || r == '\n':
+		return 0
+	case r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+		return 1
+	}
+	return 2
+}
+
+// viNextWordStart returns the start of the word after pos (the w motion),
+// or the buffer end.
+func (i *Input) viNextWordStart(pos int) int {
+	n := len(i.buffer)
+	if pos >= n {
+		return n
+	}
+
+	if class := viClass(i.buffer[pos]); class != 0 {
+		for pos < n && viClass(i.buffer[pos]) == class {
+			pos++
+		}
+	}
+	for pos < n && viClass(i.buffer[pos]) == 0 {
+		pos++
+	}
+	return pos
+}
+
+// viPrevWordStart returns the start of the word before pos (the b motion),
+// or 0.
+func (i *Input) viPrevWordStart(pos int) int {
+	pos--
+	for pos > 0 && viClass(i.buffer[pos]) == 0 {
+		pos--
+	}
+	if pos <= 0 {
+		return 0
+	}
+
+	class := viClass(i.buffer[pos])
+	for pos > 0 && viClass(i.buffer[pos-1]) == class {
+		pos--
+	}
+	return pos
+}
+
+// viWordEnd returns the last character of the word ending after pos
+// (the e motion), or the last character of the buffer.
+func (i *Input) viWordEnd(pos int) int {
+	n := len(i.buffer)
+	pos++
+	for pos < n && viClass(i.buffer[pos]) == 0 {
+		pos++
+	}
+	if pos >= n {
+		return max(n-1, 0)
+	}
+
+	class := viClass(i.buffer[pos])
+	for pos+1 < n && viClass(i.buffer[pos+1]) == class {
+		pos++
+	}
+	return pos
+}
+
+// clampViCursor keeps the normal mode cursor on a character of its line
+// rather than after the line's end.
+func (i *Input) clampViCursor() {
+	i.cursorPos = min(i.cursorPos, len(i.buffer))
+	if i.cursorPos > i.findLineStart() && (i.cursorPos == len(i.buffer) || i.buffer[i.cursorPos] == '\n') {
+		i.cursorPos--
+	}
+}
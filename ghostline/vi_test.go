@@ -0,0 +1,468 @@
+package ghostline
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newViInput returns an input in vi normal mode.
+func newViInput(buffer string, cursorPos int) *Input {
+	input := newTestInput(buffer, cursorPos)
+	input.handlers = defaultHandlers()
+	input.SetEditMode(ViMode)
+	input.vi.normal = true
+	return input
+}
+
+func TestParseViCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		keys string
+		cmd  viCommand
+		done bool
+		ok   bool
+	}{
+		{"w", viCommand{key: 'w'}, true, true},
+		{"3w", viCommand{count: 3, key: 'w'}, true, true},
+		{"0", viCommand{key: '0'}, true, true},
+		{"10l", viCommand{count: 10, key: 'l'}, true, true},
+		{"d", viCommand{op: 'd'}, false, true},
+		{"dw", viCommand{op: 'd', key: 'w'}, true, true},
+		{"2d3w", viCommand{count: 6, op: 'd', key: 'w'}, true, true},
+		{"dd", viCommand{op: 'd', key: 'd'}, true, true},
+		{"f", viCommand{key: 'f'}, false, true},
+		{"fx", viCommand{key: 'f', arg: 'x'}, true, true},
+		{"ct;", viCommand{op: 'c', key: 't', arg: ';'}, true, true},
+		{"dx", viCommand{op: 'd', key: 'x'}, false, false},
+		{"z", viCommand{key: 'z'}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.keys, func(t *testing.T) {
+			t.Parallel()
+
+			cmd, done, ok := parseViCommand([]rune(tt.keys))
+
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.done, done)
+			if tt.ok {
+				assert.Equal(t, tt.cmd, cmd)
+			}
+		})
+	}
+}
+
+func TestViMotions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		buffer string
+		cursor int
+		keys   string
+		want   int
+	}{
+		{"h moves left", "hello", 3, "h", 2},
+		{"h stops at line start", "ab\ncd", 3, "h", 3},
+		{"l stays on last character", "hello", 3, "5l", 4},
+		{"w moves to next word", "foo bar baz", 0, "w", 4},
+		{"w stops at punctuation", "foo.bar", 0, "w", 3},
+		{"w with count", "foo bar baz", 0, "2w", 8},
+		{"b moves to word start", "foo bar baz", 9, "b", 8},
+		{"b skips blanks", "foo bar baz", 8, "b", 4},
+		{"e moves to word end", "foo bar", 0, "e", 2},
+		{"e from word end moves to next", "foo bar", 2, "e", 6},
+		{"0 moves to line start", "ab\ncdef", 5, "0", 3},
+		{"^ moves to first non-blank", "   foo", 5, "^", 3},
+		{"$ moves to last character", "ab\ncdef\ngh", 3, "$", 6},
+		{"f finds character", "a,b,c", 0, "f,", 1},
+		{"f with count", "a,b,c", 0, "2f,", 3},
+		{"t stops before character", "a,b,c", 0, "t,", 0},
+		{"F finds backward", "a,b,c", 4, "F,", 3},
+		{"T stops after character", "a,b,c", 4, "T,", 4},
+		{"failed f stays", "abc", 0, "fz", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			input := newViInput(tt.buffer, tt.cursor)
+
+			pressKeys(input, tt.keys)
+
+			assert.Equal(t, tt.want, input.cursorPos)
+			assert.Equal(t, tt.buffer, string(input.buffer))
+		})
+	}
+}
+
+func TestViOperators(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		buffer     string
+		cursor     int
+		keys       string
+		wantBuffer string
+		wantCursor int
+	}{
+		{"dw deletes word and blanks", "foo bar baz", 4, "dw", "foo baz", 4},
+		{"d2w deletes two words", "foo bar baz", 0, "d2w", "baz", 0},
+		{"de deletes to word end", "foo bar", 0, "de", " bar", 0},
+		{"d$ deletes to line end", "foo bar", 3, "d$", "foo", 2},
+		{"db deletes to word start", "foo bar", 6, "db", "foo r", 4},
+		{"dt deletes until character", "call(a, b)", 0, "dt(", "(a, b)", 0},
+		{"df deletes through character", "call(a, b)", 0, "df(", "a, b)", 0},
+		{"dd deletes line", "one\ntwo\nthree", 5, "dd", "one\nthree", 4},
+		{"dd deletes last line", "one\ntwo", 5, "dd", "one", 0},
+		{"2dd deletes two lines", "one\ntwo\nthree", 0, "2dd", "three", 0},
+		{"x deletes character", "hello", 1, "x", "hllo", 1},
+		{"3x deletes characters", "hello", 1, "3x", "ho", 1},
+		{"x at end moves cursor back", "hello", 4, "x", "hell", 3},
+		{"X deletes before cursor", "hello", 2, "X", "hllo", 1},
+		{"D deletes to line end", "hello world", 5, "D", "hello", 4},
+		{"yw then P puts before cursor", "foo bar", 4, "ywP", "foo barbar", 6},
+		{"yy then p puts line below", "one\ntwo", 1, "yyp", "one\none\ntwo", 4},
+		{"yy then P puts line above", "one\ntwo", 5, "yyP", "one\ntwo\ntwo", 4},
+		{"dd then p moves line down", "one\ntwo", 0, "ddp", "two\none", 4},
+		{"x then p swaps characters", "ab", 0, "xp", "ba", 1},
+		{"2p puts twice", "ab", 0, "yl2p", "aaab", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			input := newViInput(tt.buffer, tt.cursor)
+
+			pressKeys(input, tt.keys)
+
+			assert.Equal(t, tt.wantBuffer, string(input.buffer))
+			assert.Equal(t, tt.wantCursor, input.cursorPos)
+			assert.True(t, input.vi.normal)
+		})
+	}
+}
+
+func TestViInsertMode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		buffer     string
+		cursor     int
+		keys       string
+		wantBuffer string
+		wantCursor int
+	}{
+		{"i inserts before cursor", "ac", 1, "ib\x1b", "abc", 1},
+		{"a appends after cursor", "ac", 0, "ab\x1b", "abc", 1},
+		{"I inserts at first non-blank", "  bc", 3, "Ia\x1b", "  abc", 2},
+		{"A appends at line end", "ab", 0, "Ac\x1b", "abc", 2},
+		{"cw changes to word end", "foo bar", 0, "cwbaz\x1b", "baz bar", 2},
+		{"cc changes line", "one\ntwo", 5, "ccnew\x1b", "one\nnew", 6},
+		{"C changes to line end", "foo bar", 4, "Cbaz\x1b", "foo baz", 6},
+		{"s substitutes character", "cat", 0, "sb\x1b", "bat", 0},
+		{"S substitutes line", "cat", 1, "Sdog\x1b", "dog", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			input := newViInput(tt.buffer, tt.cursor)
+
+			pressKeys(input, tt.keys)
+
+			assert.Equal(t, tt.wantBuffer, string(input.buffer))
+			assert.Equal(t, tt.wantCursor, input.cursorPos)
+			assert.True(t, input.vi.normal)
+		})
+	}
+
+	t.Run("Escape enters normal mode", func(t *testing.T) {
+		t.Parallel()
+
+		input := newTestInput("abc", 3)
+		input.handlers = defaultHandlers()
+		input.SetEditMode(ViMode)
+
+		pressKeys(input, "\x1b")
+
+		assert.True(t, input.vi.normal)
+		assert.Equal(t, 2, input.cursorPos)
+	})
+}
+
+func TestViRepeat(t *testing.T) {
+	t.Parallel()
+
+	t.Run("dot repeats delete", func(t *testing.T) {
+		t.Parallel()
+
+		input := newViInput("a b c d", 0)
+
+		pressKeys(input, "dw..")
+
+		assert.Equal(t, "d", string(input.buffer))
+	})
+
+	t.Run("dot repeats change with inserted text", func(t *testing.T) {
+		t.Parallel()
+
+		input := newViInput("foo foo", 0)
+
+		pressKeys(input, "cwbar\x1bw.")
+
+		assert.Equal(t, "bar bar", string(input.buffer))
+		assert.Equal(t, 6, input.cursorPos)
+	})
+
+	t.Run("dot repeats insert", func(t *testing.T) {
+		t.Parallel()
+
+		input := newViInput("x", 0)
+
+		pressKeys(input, "A!\x1b.")
+
+		assert.Equal(t, "x!!", string(input.buffer))
+	})
+
+	t.Run("count replaces original count", func(t *testing.T) {
+		t.Parallel()
+
+		input := newViInput("abcdef", 0)
+
+		pressKeys(input, "x3.")
+
+		assert.Equal(t, "ef", string(input.buffer))
+	})
+
+	t.Run("motions and yanks are not repeated", func(t *testing.T) {
+		t.Parallel()
+
+		input := newViInput("ab cd", 0)
+
+		pressKeys(input, "xwyl.")
+
+		assert.Equal(t, "b d", string(input.buffer))
+	})
+}
+
+func TestViUndo(t *testing.T) {
+	t.Parallel()
+
+	t.Run("u undoes last change", func(t *testing.T) {
+		t.Parallel()
+
+		input := newViInput("foo bar", 0)
+
+		pressKeys(input, "dwu")
+
+		assert.Equal(t, "foo bar", string(input.buffer))
+		assert.Equal(t, 0, input.cursorPos)
+	})
+
+	t.Run("insert session is one step", func(t *testing.T) {
+		t.Parallel()
+
+		input := newViInput("x", 0)
+
+		pressKeys(input, "aabc\x1bu")
+
+		assert.Equal(t, "x", string(input.buffer))
+	})
+
+	t.Run("change and its insert are one step", func(t *testing.T) {
+		t.Parallel()
+
+		input := newViInput("foo bar", 0)
+
+		pressKeys(input, "cwbaz\x1bu")
+
+		assert.Equal(t, "foo bar", string(input.buffer))
+	})
+
+	t.Run("count undoes several steps", func(t *testing.T) {
+		t.Parallel()
+
+		input := newViInput("abcd", 0)
+
+		pressKeys(input, "xxx2u")
+
+		assert.Equal(t, "bcd", string(input.buffer))
+	})
+
+	t.Run("u with nothing to undo", func(t *testing.T) {
+		t.Parallel()
+
+		input := newViInput("abc", 1)
+
+		pressKeys(input, "u")
+
+		assert.Equal(t, "abc", string(input.buffer))
+		assert.Equal(t, 1, input.cursorPos)
+	})
+}
+
+func TestViNormalMode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Escape cancels pending command", func(t *testing.T) {
+		t.Parallel()
+
+		input := newViInput("foo bar", 0)
+
+		pressKeys(input, "d\x1bw")
+
+		assert.Equal(t, "foo bar", string(input.buffer))
+		assert.Equal(t, 4, input.cursorPos)
+	})
+
+	t.Run("invalid keys are dropped", func(t *testing.T) {
+		t.Parallel()
+
+		input := newViInput("foo bar", 0)
+
+		pressKeys(input, "dzw")
+
+		assert.Equal(t, "foo bar", string(input.buffer))
+		assert.Equal(t, 4, input.cursorPos)
+		assert.Empty(t, input.vi.pending)
+	})
+
+	t.Run("k and j browse history", func(t *testing.T) {
+		t.Parallel()
+
+		input := newViInput("draft", 0)
+		input.history.Add("first")
+		input.history.Add("second")
+
+		pressKeys(input, "kk")
+		assert.Equal(t, "first", string(input.buffer))
+
+		pressKeys(input, "jj")
+		assert.Equal(t, "draft", string(input.buffer))
+	})
+
+	t.Run("control keys use regular handlers", func(t *testing.T) {
+		t.Parallel()
+
+		input := newViInput("hello", 2)
+
+		result, act := input.handleKey(keyEnter, bufio.NewReader(strings.NewReader("")))
+
+		assert.Equal(t, actionSubmit, act)
+		assert.Equal(t, "hello", result)
+	})
+}
+
+func TestViModeIndicator(t *testing.T) {
+	t.Parallel()
+
+	input := newTestInput("ls", 2)
+	input.handlers = defaultHandlers()
+	input.prompt = "$ "
+	input.SetEditMode(ViMode)
+
+	input.render()
+	require.Contains(t, input.out.(*bytes.Buffer).String(), "(ins) $ ls")
+
+	pressKeys(input, "\x1b")
+	assert.Contains(t, input.out.(*bytes.Buffer).String(), "(cmd) $ ls")
+
+	input.SetEditMode(EmacsMode)
+	assert.Nil(t, input.vi)
+	assert.Equal(t, "$ ", input.firstPrompt())
+}
+
+func TestViEscapeTimeout(t *testing.T) {
+	t.Parallel()
+
+	// feed returns an input reading keys from a pipe, as Readline does.
+	feed := func(t *testing.T, input *Input) (*io.PipeWriter, *bufio.Reader) {
+		t.Helper()
+		pr, pw := io.Pipe()
+		reader := bufio.NewReader(pr)
+		input.keys = newKeyReader(reader)
+		t.Cleanup(func() {
+			pw.Close()
+			input.keys.close()
+		})
+		return pw, reader
+	}
+
+	t.Run("arrow key split across reads moves the cursor", func(t *testing.T) {
+		t.Parallel()
+
+		input := newViInput("abc", 2)
+		pw, reader := feed(t, input)
+		go func() {
+			_, _ = pw.Write([]byte("\x1b"))
+			time.Sleep(10 * time.Millisecond)
+			_, _ = pw.Write([]byte("[D"))
+		}()
+
+		r, err := input.readKey(input.keys)
+		require.NoError(t, err)
+		input.handleKey(r, reader)
+
+		assert.Equal(t, "abc", string(input.buffer))
+		assert.Equal(t, 1, input.cursorPos)
+		assert.True(t, input.vi.normal)
+	})
+
+	t.Run("arrow key split across reads stays in insert mode", func(t *testing.T) {
+		t.Parallel()
+
+		input := newViInput("abc", 2)
+		input.vi.normal = false
+		pw, reader := feed(t, input)
+		go func() {
+			_, _ = pw.Write([]byte("\x1b"))
+			time.Sleep(10 * time.Millisecond)
+			_, _ = pw.Write([]byte("[D"))
+		}()
+
+		r, err := input.readKey(input.keys)
+		require.NoError(t, err)
+		input.handleKey(r, reader)
+
+		assert.Equal(t, "abc", string(input.buffer))
+		assert.Equal(t, 1, input.cursorPos)
+		assert.False(t, input.vi.normal)
+	})
+
+	t.Run("lone Escape enters normal mode after the timeout", func(t *testing.T) {
+		t.Parallel()
+
+		input := newViInput("abc", 3)
+		input.vi.normal = false
+		pw, reader := feed(t, input)
+		go func() {
+			_, _ = pw.Write([]byte("\x1b"))
+			time.Sleep(4 * escTimeout)
+			_, _ = pw.Write([]byte("x"))
+		}()
+
+		r, err := input.readKey(input.keys)
+		require.NoError(t, err)
+		input.handleKey(r, reader)
+		assert.True(t, input.vi.normal)
+
+		r, err = input.readKey(input.keys)
+		require.NoError(t, err)
+		input.handleKey(r, reader)
+		assert.Equal(t, "ab", string(input.buffer))
+	})
+}